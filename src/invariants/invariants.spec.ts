@@ -164,6 +164,37 @@ describe('ruleValidator', () => {
       expect(errors.some(e => e.message.includes('regex') || e.message.includes('Invalid'))).toBe(true);
     });
 
+    it('accepts except globs on a module-boundary rule', () => {
+      const errors = validateRulesConfig({
+        rules: [
+          {
+            id: 'r1',
+            kind: 'module-boundary',
+            severity: 'critical',
+            from: 'src/a/**',
+            to: 'src/b/**',
+            except: ['src/a/legacy/**'],
+          },
+        ],
+      });
+      expect(errors).toHaveLength(0);
+    });
+
+    it('accepts a replacement on an import-ban rule', () => {
+      const errors = validateRulesConfig({
+        rules: [
+          {
+            id: 'r1',
+            kind: 'import-ban',
+            severity: 'warning',
+            module: 'io/ioutil',
+            replacement: 'os',
+          },
+        ],
+      });
+      expect(errors).toHaveLength(0);
+    });
+
     it('rejects unknown fields on a rule', () => {
       const errors = validateRulesConfig({
         rules: [
@@ -236,6 +267,37 @@ describe('ruleValidator', () => {
       expect(errors).toHaveLength(0);
     });
 
+    it('accepts constraints on a Go ast-pattern rule', () => {
+      const errors = validateRulesConfig({
+        rules: [
+          {
+            id: 'no-ignored-close',
+            kind: 'ast-pattern',
+            severity: 'warning',
+            language: 'go',
+            pattern: '$X.Close()',
+            constraints: { $X: { implements: 'io.Closer', regex: '^f' } },
+          },
+        ],
+      });
+      expect(errors).toHaveLength(0);
+    });
+
+    it('rejects constraints on a non-Go ast-pattern rule', () => {
+      const errors = validateRulesConfig({
+        rules: [
+          {
+            id: 'r1',
+            kind: 'ast-pattern',
+            severity: 'warning',
+            pattern: 'new Function($$$)',
+            constraints: { $X: { regex: '^f' } },
+          },
+        ],
+      });
+      expect(errors.some(e => e.message.includes('language "go"'))).toBe(true);
+    });
+
     it('accepts ast-pattern with optional language', () => {
       const errors = validateRulesConfig({
         rules: [
@@ -432,6 +494,25 @@ describe('import-ban', () => {
     expect(result.violations).toHaveLength(0);
   });
 
+  it('suggests the replacement module in the default message', () => {
+    writeFixture('src/bad.ts', `import { x } from 'banned-lib';`);
+    const result = checkRules({
+      rules: [
+        makeRule({
+          id: 'no-banned',
+          kind: 'import-ban',
+          severity: 'warning',
+          module: 'banned-lib',
+          replacement: 'approved-lib',
+        }),
+      ],
+      files: ['src/bad.ts'],
+      projectDir: testDir,
+    });
+    expect(result.violations).toHaveLength(1);
+    expect(result.violations[0].message).toBe('Import of banned module "banned-lib"; use "approved-lib" instead');
+  });
+
   it('includes the user message in violations', () => {
     writeFixture('src/bad.ts', `import { x } from 'banned';`);
     const result = checkRules({
@@ -516,6 +597,29 @@ describe('module-boundary', () => {
     // from=src/features/b/** doesn't match src/features/c/consumer.ts
     expect(result.violations).toHaveLength(0);
   });
+  it('allows imports from or into paths matching except globs', () => {
+    writeFixture('src/features/a/index.ts', `export const a = 1;`);
+    writeFixture('src/features/a/public.ts', `export const p = 1;`);
+    writeFixture('src/features/b/legacy.ts', `import { a } from '../a/index';`);
+    writeFixture('src/features/b/messy.ts', `import { a } from '../a/index';\nimport { p } from '../a/public';`);
+    const result = checkRules({
+      rules: [
+        makeRule({
+          id: 'no-cross-feature',
+          kind: 'module-boundary',
+          severity: 'critical',
+          from: 'src/features/b/**',
+          to: 'src/features/a/**',
+          except: ['src/features/b/legacy.ts', 'src/features/a/public.ts'],
+        }),
+      ],
+      files: ['src/features/b/legacy.ts', 'src/features/b/messy.ts'],
+      projectDir: testDir,
+    });
+    expect(result.violations).toHaveLength(1);
+    expect(result.violations[0].file).toBe('src/features/b/messy.ts');
+    expect(result.violations[0].importSpecifier).toBe('../a/index');
+  });
 });
 
 // ── R2.4: naming ─────────────────────────────────────────────────────────────
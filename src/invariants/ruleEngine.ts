@@ -161,7 +161,9 @@ function checkImportBan(
         ruleId: rule.id,
         kind: 'import-ban',
         severity: rule.severity,
-        message: rule.message || `Import of banned module "${imp.moduleSpecifier}"`,
+        message: rule.message || (rule.replacement
+          ? `Import of banned module "${imp.moduleSpecifier}"; use "${rule.replacement}" instead`
+          : `Import of banned module "${imp.moduleSpecifier}"`),
         file: filePath,
         line: imp.line,
         importSpecifier: imp.moduleSpecifier,
@@ -182,14 +184,17 @@ function checkModuleBoundary(
 ): RuleViolation[] {
   const violations: RuleViolation[] = [];
 
-  // Only check files matching `from`
-  if (!matchesPattern(rule.from, filePath)) {
+  // Only check files matching `from` that are not exempt
+  if (!matchesPattern(rule.from, filePath) || (rule.except && matchesAny(rule.except, filePath))) {
     return violations;
   }
 
   for (const imp of imports) {
     // Only check relative imports (inter-module) and internal absolute imports
     const resolved = resolveImportPath(filePath, imp.moduleSpecifier);
+    if (resolved && rule.except && matchesAny(rule.except, resolved)) {
+      continue;
+    }
     if (resolved && matchesPattern(rule.to, resolved)) {
       violations.push({
         ruleId: rule.id,
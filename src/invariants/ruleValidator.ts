@@ -122,6 +122,9 @@ function validateRule(rule: InvariantRule): RuleValidationError[] {
           }
         }
       }
+      if (rule.replacement !== undefined && (typeof rule.replacement !== 'string' || rule.replacement.length === 0)) {
+        errors.push({ ruleId: rule.id, message: '"replacement" must be a non-empty module string' });
+      }
       break;
     }
 
@@ -170,6 +173,17 @@ function validateRule(rule: InvariantRule): RuleValidationError[] {
       } else if (!isValidGlob(rule.to)) {
         errors.push({ ruleId: rule.id, message: `Invalid glob pattern in "to": "${rule.to}"` });
       }
+
+      if (rule.except !== undefined && !Array.isArray(rule.except)) {
+        errors.push({ ruleId: rule.id, message: '"except" must be an array of path glob strings' });
+      }
+      if (Array.isArray(rule.except)) {
+        for (const g of rule.except) {
+          if (!isValidGlob(g)) {
+            errors.push({ ruleId: rule.id, message: `Invalid glob pattern in "except": "${g}"` });
+          }
+        }
+      }
       break;
     }
 
@@ -218,6 +232,29 @@ function validateRule(rule: InvariantRule): RuleValidationError[] {
           });
         }
       }
+
+      if (rule.constraints !== undefined) {
+        if (rule.language !== 'go') {
+          errors.push({ ruleId: rule.id, message: '"constraints" are only supported with language "go"' });
+        }
+        if (typeof rule.constraints !== 'object' || rule.constraints === null || Array.isArray(rule.constraints)) {
+          errors.push({ ruleId: rule.id, message: '"constraints" must be an object keyed by metavariable name' });
+        } else {
+          for (const [name, constraint] of Object.entries(rule.constraints)) {
+            if (constraint?.regex === undefined) {
+              continue;
+            }
+            try {
+              new RegExp(constraint.regex);
+            } catch {
+              errors.push({
+                ruleId: rule.id,
+                message: `Invalid regex in constraint "${name}": "${constraint.regex}"`,
+              });
+            }
+          }
+        }
+      }
       break;
     }
 
@@ -284,20 +321,20 @@ function validateRule(rule: InvariantRule): RuleValidationError[] {
   // Check for unknown fields
   const knownFields = new Set([
     'id', 'kind', 'severity', 'message',
-    'module', 'except',           // import-ban
+    'module', 'except', 'replacement', // import-ban
     'callee', 'allowFrom', 'denyFrom', // call-constraint
     'from', 'to',                 // module-boundary
     'path', 'exports',            // naming
-    'pattern', 'language',         // ast-pattern
+    'pattern', 'language', 'constraints', // ast-pattern
     'allow',                       // style-mechanism
     'properties', 'allowValues',   // no-raw-values
   ]);
   const kindFields: Record<RuleKind, Set<string>> = {
-    'import-ban': new Set(['id', 'kind', 'severity', 'message', 'module', 'except']),
+    'import-ban': new Set(['id', 'kind', 'severity', 'message', 'module', 'except', 'replacement']),
     'call-constraint': new Set(['id', 'kind', 'severity', 'message', 'callee', 'allowFrom', 'denyFrom']),
-    'module-boundary': new Set(['id', 'kind', 'severity', 'message', 'from', 'to']),
+    'module-boundary': new Set(['id', 'kind', 'severity', 'message', 'from', 'to', 'except']),
     'naming': new Set(['id', 'kind', 'severity', 'message', 'path', 'exports']),
-    'ast-pattern': new Set(['id', 'kind', 'severity', 'message', 'pattern', 'language', 'path']),
+    'ast-pattern': new Set(['id', 'kind', 'severity', 'message', 'pattern', 'language', 'path', 'constraints']),
     'style-mechanism': new Set(['id', 'kind', 'severity', 'message', 'allow', 'path']),
     'no-raw-values': new Set(['id', 'kind', 'severity', 'message', 'properties', 'allowValues', 'path']),
   };
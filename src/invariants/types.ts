@@ -14,11 +14,13 @@ export interface RuleBase {
 }
 
 /** import-ban: prevent importing a given module (glob) from any file,
- *  unless the importing file matches an `except` path glob. */
+ *  unless the importing file matches an `except` path glob.
+ *  `replacement` names the module to import instead. */
 export interface ImportBanRule extends RuleBase {
   kind: 'import-ban';
   module: string;
   except?: string[];
+  replacement?: string;
 }
 
 /** call-constraint: allow or deny callers of a function.
@@ -30,11 +32,13 @@ export interface CallConstraintRule extends RuleBase {
   denyFrom?: string[];     // callers matching these path globs are denied
 }
 
-/** module-boundary: files matching `from` may not import from files matching `to`. */
+/** module-boundary: files matching `from` may not import from files matching `to`,
+ *  unless the importing file or the imported path matches an `except` glob. */
 export interface ModuleBoundaryRule extends RuleBase {
   kind: 'module-boundary';
   from: string;
   to: string;
+  except?: string[];
 }
 
 /** naming: exported symbols in files matching `path` must match the `exports` regex. */
@@ -44,13 +48,22 @@ export interface NamingRule extends RuleBase {
   exports: string;  // regex pattern
 }
 
+/** Restricts what an ast-pattern metavariable may bind (Go patterns only). */
+export interface MetavariableConstraint {
+  type?: string;        // exact type, e.g. 'error' or '*sql.DB'
+  implements?: string;  // interface the type must implement, e.g. 'fmt.Stringer'
+  regex?: string;       // expression the bound source text must match
+}
+
 /** ast-pattern: match AST nodes using @ast-grep/napi pattern syntax.
- *  Optionally restrict to files matching `path` and/or a specific `language`. */
+ *  Optionally restrict to files matching `path` and/or a specific `language`.
+ *  `constraints`, keyed by metavariable name, apply to Go patterns. */
 export interface AstPatternRule extends RuleBase {
   kind: 'ast-pattern';
   pattern: string;
   language?: 'typescript' | 'javascript' | 'go';
   path?: string;
+  constraints?: Record<string, MetavariableConstraint>;
 }
 
 /** style-mechanism: enforce that style declarations in matching files
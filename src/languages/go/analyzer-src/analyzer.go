@@ -1,10 +1,16 @@
 package analyzer
 
 import (
+	"fmt"
+	"sort"
 	"strings"
 	"time"
 )
 
+// Version is the analyzer version reported to clients and part of every
+// cache key
+const Version = "1.0.0"
+
 // Analyzer is the main Go code analyzer
 type Analyzer struct {
 	options AnalysisOptions
@@ -29,87 +35,103 @@ func (a *Analyzer) Analyze(files []string) (*AnalysisResult, error) {
 		return nil, err
 	}
 
-	result := &AnalysisResult{
-		Violations:   []Violation{},
-		IndexEntries: []IndexEntry{},
-		Metrics: Metrics{
-			FilesAnalyzed: int64(len(files)),
-			ExecutionTime: 0, // Will be set at the end
-		},
-		Errors: []Error{},
-	}
-
-	// Run enabled analyzers
-	for _, analyzerName := range a.options.Analyzers {
-		switch analyzerName {
-		case "solid":
-			violations := a.runSOLIDAnalysis()
-			result.Violations = append(result.Violations, violations...)
-		case "imports":
-			violations := a.runImportAnalysis()
-			result.Violations = append(result.Violations, violations...)
-		case "errors":
-			violations := a.runErrorAnalysis()
-			result.Violations = append(result.Violations, violations...)
-		case "goroutines":
-			violations := a.runGoroutineAnalysis()
-			result.Violations = append(result.Violations, violations...)
-		case "channels":
-			violations := a.runChannelAnalysis()
-			result.Violations = append(result.Violations, violations...)
+	// Changed files are analyzed with the rest of their packages
+	if a.changeScoped() {
+		for _, filePath := range files {
+			if err := a.parser.LoadPackageContext(filePath); err != nil {
+				return nil, err
+			}
 		}
 	}
 
-	// Generate index entries
-	indexer := NewIndexer(a.parser)
-	result.IndexEntries = indexer.GenerateIndexEntries()
-
-	// Filter violations by severity
-	result.Violations = a.filterViolationsBySeverity(result.Violations)
-
-	// Calculate execution time
-	result.Metrics.ExecutionTime = time.Since(startTime).Milliseconds()
+	// Reuse stored results for unchanged files when a cache is configured
+	if a.options.CacheDir != "" {
+		return a.analyzeCached(len(files), startTime), nil
+	}
 
-	return result, nil
+	return a.run(len(files), startTime), nil
 }
 
 // AnalyzeContent performs analysis of Go content from a string
 func (a *Analyzer) AnalyzeContent(filePath, content string) (*AnalysisResult, error) {
+	return a.AnalyzeOverlay(map[string]string{filePath: content})
+}
+
+// AnalyzeOverlay analyzes in-memory file contents layered on top of the
+// on-disk files of their packages. Only the overlaid files are reported;
+// unchanged siblings are loaded as context for package-level rules.
+func (a *Analyzer) AnalyzeOverlay(overlay map[string]string) (*AnalysisResult, error) {
 	startTime := time.Now()
 
-	// Parse content instead of file
-	if err := a.parser.ParseContent(filePath, content); err != nil {
-		return nil, err
+	paths := make([]string, 0, len(overlay))
+	for filePath := range overlay {
+		paths = append(paths, filePath)
+	}
+	sort.Strings(paths)
+
+	// Parse content instead of files
+	for _, filePath := range paths {
+		if err := a.parser.ParseContent(filePath, overlay[filePath]); err != nil {
+			return nil, err
+		}
+	}
+	for _, filePath := range paths {
+		if err := a.parser.LoadPackageContext(filePath); err != nil {
+			return nil, err
+		}
 	}
 
+	return a.run(len(paths), startTime), nil
+}
+
+// run executes the enabled checks over the parsed files and applies the
+// shared post-processing: context file filtering, settings, suppressions,
+// the baseline and the severity filter
+func (a *Analyzer) run(fileCount int, startTime time.Time) *AnalysisResult {
+	result := a.collect(fileCount)
+	a.finish(result, startTime)
+	return result
+}
+
+// collect runs the enabled checks and resolves the per-file results:
+// findings in context files are dropped, then settings and suppression
+// directives are applied
+func (a *Analyzer) collect(fileCount int) *AnalysisResult {
 	result := &AnalysisResult{
 		Violations:   []Violation{},
 		IndexEntries: []IndexEntry{},
 		Metrics: Metrics{
-			FilesAnalyzed: 1,
+			FilesAnalyzed: int64(fileCount),
 			ExecutionTime: 0, // Will be set at the end
 		},
-		Errors: []Error{},
+		Errors:   a.parser.settingsErrors(),
+		Settings: a.parser.effectiveSettings(),
 	}
 
-	// Run enabled analyzers
-	for _, analyzerName := range a.options.Analyzers {
-		switch analyzerName {
-		case "solid":
-			violations := a.runSOLIDAnalysis()
-			result.Violations = append(result.Violations, violations...)
-		case "imports":
-			violations := a.runImportAnalysis()
-			result.Violations = append(result.Violations, violations...)
-		case "errors":
-			violations := a.runErrorAnalysis()
-			result.Violations = append(result.Violations, violations...)
-		case "goroutines":
-			violations := a.runGoroutineAnalysis()
-			result.Violations = append(result.Violations, violations...)
-		case "channels":
-			violations := a.runChannelAnalysis()
+	// Run enabled analyzers after the checks they require; only the
+	// violations of enabled ones are reported
+	checks, errors := a.enabledChecks()
+	result.Errors = append(result.Errors, errors...)
+	enabled := make(map[string]bool)
+	for _, check := range checks {
+		enabled[check.Name()] = true
+	}
+	checks, errors = scheduleChecks(checks)
+	result.Errors = append(result.Errors, errors...)
+
+	facts := make(map[factKey]Fact)
+	for _, check := range checks {
+		if check.Requirements().TypeInfo && a.options.SkipTypeCheck && a.options.Verbose {
+			result.Errors = append(result.Errors, Error{
+				Message: fmt.Sprintf("Analyzer %q runs without type information; results may be less precise", check.Name()),
+				Type:    "requirement",
+			})
+		}
+		pass := &Pass{Check: check, Parser: a.parser, analyzer: a, facts: facts}
+		violations := check.Run(pass)
+		if enabled[check.Name()] {
 			result.Violations = append(result.Violations, violations...)
+			result.Errors = append(result.Errors, pass.errors...)
 		}
 	}
 
@@ -117,62 +139,150 @@ func (a *Analyzer) AnalyzeContent(filePath, content string) (*AnalysisResult, er
 	indexer := NewIndexer(a.parser)
 	result.IndexEntries = indexer.GenerateIndexEntries()
 
+	// Drop findings in files that were only loaded as context
+	result.Violations = a.withoutContextFiles(result.Violations)
+	result.IndexEntries = a.indexEntriesWithoutContextFiles(result.IndexEntries)
+
+	// Apply analyzer settings and inline suppression directives
+	result.Violations = a.parser.applySettings(result.Violations)
+	result.Violations, result.Suppressed = a.applySuppressions(a.parser.withFingerprints(result.Violations))
+
+	return result
+}
+
+// finish applies the baseline, the change scope and the severity filter to
+// collected results, orders them and fills in the metrics
+func (a *Analyzer) finish(result *AnalysisResult, startTime time.Time) {
+	a.applyBaseline(result)
+	a.applyChangeScope(result)
+
 	// Filter violations by severity
 	result.Violations = a.filterViolationsBySeverity(result.Violations)
 
+	// Report in a stable order; parsed files are kept in a map
+	sortViolations(result.Violations)
+	sortSuppressed(result.Suppressed)
+	sortIndexEntries(result.IndexEntries)
+
+	// Package-level metrics
+	packages, modules := a.parser.PackageAndModuleCounts()
+	result.Metrics.PackagesAnalyzed = int64(packages)
+	result.Metrics.ModulesAnalyzed = int64(modules)
+
 	// Calculate execution time
 	result.Metrics.ExecutionTime = time.Since(startTime).Milliseconds()
+}
 
-	return result, nil
+// sortViolations orders violations by file, position, analyzer and category
+func sortViolations(violations []Violation) {
+	sort.SliceStable(violations, func(i, j int) bool {
+		return violationLess(violations[i], violations[j])
+	})
+}
+
+// sortSuppressed orders suppressed violations like reported ones
+func sortSuppressed(suppressed []SuppressedViolation) {
+	sort.SliceStable(suppressed, func(i, j int) bool {
+		return violationLess(suppressed[i].Violation, suppressed[j].Violation)
+	})
+}
+
+// violationLess compares violations by file, line, column, analyzer,
+// category, end position and message
+func violationLess(a, b Violation) bool {
+	switch {
+	case a.File != b.File:
+		return a.File < b.File
+	case a.Line != b.Line:
+		return a.Line < b.Line
+	case a.Column != b.Column:
+		return a.Column < b.Column
+	case a.Analyzer != b.Analyzer:
+		return a.Analyzer < b.Analyzer
+	case a.Category != b.Category:
+		return a.Category < b.Category
+	case a.EndLine != b.EndLine:
+		return a.EndLine < b.EndLine
+	case a.EndColumn != b.EndColumn:
+		return a.EndColumn < b.EndColumn
+	}
+	return a.Message < b.Message
+}
+
+// sortIndexEntries orders index entries by file, start line, name and type
+func sortIndexEntries(entries []IndexEntry) {
+	sort.SliceStable(entries, func(i, j int) bool {
+		a, b := entries[i], entries[j]
+		switch {
+		case a.File != b.File:
+			return a.File < b.File
+		case a.StartLine != b.StartLine:
+			return a.StartLine < b.StartLine
+		case a.Name != b.Name:
+			return a.Name < b.Name
+		}
+		return a.Type < b.Type
+	})
+}
+
+// withoutContextFiles drops violations reported in package context files
+func (a *Analyzer) withoutContextFiles(violations []Violation) []Violation {
+	filtered := []Violation{}
+	for _, violation := range violations {
+		if !a.parser.IsContextFile(violation.File) {
+			filtered = append(filtered, violation)
+		}
+	}
+	return filtered
+}
+
+// indexEntriesWithoutContextFiles drops index entries declared in package context files
+func (a *Analyzer) indexEntriesWithoutContextFiles(entries []IndexEntry) []IndexEntry {
+	filtered := []IndexEntry{}
+	for _, entry := range entries {
+		if !a.parser.IsContextFile(entry.File) {
+			filtered = append(filtered, entry)
+		}
+	}
+	return filtered
 }
 
 // runSOLIDAnalysis runs SOLID principle analysis
-func (a *Analyzer) runSOLIDAnalysis() []Violation {
+func (a *Analyzer) runSOLIDAnalysis(pass *Pass) []Violation {
 	solidAnalyzer := NewSOLIDAnalyzer(a.parser)
+	solidAnalyzer.pass = pass
 	return solidAnalyzer.Analyze()
 }
 
 // runImportAnalysis analyzes import usage and organization
 func (a *Analyzer) runImportAnalysis() []Violation {
-	var violations []Violation
+	importAnalyzer := NewImportAnalyzer(a.parser)
+	return importAnalyzer.Analyze()
+}
 
-	for filePath, file := range a.parser.files {
-		// Check for unused imports
-		if len(file.Imports) > 10 {
-			violations = append(violations, Violation{
-				File:     filePath,
-				Line:     1,
-				Severity: "suggestion",
-				Message:  "File has many imports - consider organizing or reducing dependencies",
-				Details: map[string]interface{}{
-					"importCount": len(file.Imports),
-				},
-				Suggestion: "Group related imports and consider if all are necessary",
-				Analyzer:   "imports",
-				Category:   "import-organization",
-			})
-		}
+// runDependencyAnalysis analyzes the package import graph
+func (a *Analyzer) runDependencyAnalysis() []Violation {
+	dependencyAnalyzer := NewDependencyAnalyzer(a.parser, a.options.Layers)
+	return dependencyAnalyzer.Analyze()
+}
 
-		// Check for dot imports (considered bad practice)
-		for _, importSpec := range file.Imports {
-			if importSpec.Name != nil && importSpec.Name.Name == "." {
-				pos := a.parser.fileSet.Position(importSpec.Pos())
-				violations = append(violations, Violation{
-					File:     filePath,
-					Line:     pos.Line,
-					Severity: "warning",
-					Message:  "Dot import detected - can lead to namespace pollution",
-					Details: map[string]interface{}{
-						"import": importSpec.Path.Value,
-					},
-					Suggestion: "Use explicit import names instead of dot imports",
-					Analyzer:   "imports",
-					Category:   "import-style",
-				})
-			}
-		}
+// runRuleAnalysis enforces the configured invariant rules
+func (a *Analyzer) runRuleAnalysis(pass *Pass) []Violation {
+	ruleAnalyzer := NewRuleAnalyzer(a.parser, a.options.Rules)
+	violations := ruleAnalyzer.Analyze()
+	for _, err := range ruleAnalyzer.Errors() {
+		pass.ReportError(err)
 	}
+	return violations
+}
 
+// runNamingAnalysis checks names against Go idioms and the configured conventions
+func (a *Analyzer) runNamingAnalysis(pass *Pass) []Violation {
+	namingAnalyzer := NewNamingAnalyzer(a.parser, a.options.Naming)
+	violations := namingAnalyzer.Analyze()
+	for _, err := range namingAnalyzer.Errors() {
+		pass.ReportError(err)
+	}
 	return violations
 }
 
@@ -187,9 +297,8 @@ func (a *Analyzer) runErrorAnalysis() []Violation {
 			// This is a simplified check - a full implementation would analyze the AST
 			// to check for proper error handling
 			if function.Complexity > 5 && !containsErrorHandling(function.Name) {
-				violations = append(violations, Violation{
+				violations = append(violations, a.parser.withEntityRange(Violation{
 					File:     function.File,
-					Line:     function.StartLine,
 					Severity: "suggestion",
 					Message:  "Function returns error but may not handle all internal errors properly",
 					Details: map[string]interface{}{
@@ -198,11 +307,14 @@ func (a *Analyzer) runErrorAnalysis() []Violation {
 					Suggestion: "Ensure all error-returning calls are properly handled",
 					Analyzer:   "errors",
 					Category:   "error-handling",
-				})
+				}, function.EntityInfo))
 			}
 		}
 	}
 
+	// fmt.Errorf calls that format errors instead of wrapping them
+	violations = append(violations, a.analyzeErrorWrapping()...)
+
 	return violations
 }
 
@@ -215,9 +327,8 @@ func (a *Analyzer) runGoroutineAnalysis() []Violation {
 	functions := a.parser.ExtractFunctions()
 	for _, function := range functions {
 		if containsGoroutine(function.Name) && !containsWaitGroup(function.Name) {
-			violations = append(violations, Violation{
+			violations = append(violations, a.parser.withEntityRange(Violation{
 				File:     function.File,
-				Line:     function.StartLine,
 				Severity: "warning",
 				Message:  "Function uses goroutines but may not properly synchronize",
 				Details: map[string]interface{}{
@@ -226,10 +337,13 @@ func (a *Analyzer) runGoroutineAnalysis() []Violation {
 				Suggestion: "Consider using sync.WaitGroup or channels for goroutine synchronization",
 				Analyzer:   "goroutines",
 				Category:   "concurrency",
-			})
+			}, function.EntityInfo))
 		}
 	}
 
+	// Cancel functions of derived contexts that are never called
+	violations = append(violations, a.analyzeLostCancel()...)
+
 	return violations
 }
 
@@ -241,10 +355,9 @@ func (a *Analyzer) runChannelAnalysis() []Violation {
 	// A full implementation would analyze the AST for channel operations
 	functions := a.parser.ExtractFunctions()
 	for _, function := range functions {
-		if containsChannel(function.Signature) && function.Complexity > 3 {
-			violations = append(violations, Violation{
+		if containsChannel(function.Signature) && function.Complexity > a.parser.threshold(function.File, "channels", "concurrency", "complexity") {
+			violations = append(violations, a.parser.withEntityRange(Violation{
 				File:     function.File,
-				Line:     function.StartLine,
 				Severity: "suggestion",
 				Message:  "Complex function uses channels - review for potential deadlocks",
 				Details: map[string]interface{}{
@@ -254,7 +367,7 @@ func (a *Analyzer) runChannelAnalysis() []Violation {
 				Suggestion: "Ensure proper channel synchronization to avoid deadlocks",
 				Analyzer:   "channels",
 				Category:   "concurrency",
-			})
+			}, function.EntityInfo))
 		}
 	}
 
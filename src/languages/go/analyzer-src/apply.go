@@ -0,0 +1,260 @@
+package analyzer
+
+import (
+	"fmt"
+	"go/format"
+	"os"
+	"path/filepath"
+	"sort"
+)
+
+// pendingFix is a fix accepted for application with the violation it resolves
+type pendingFix struct {
+	fix       Fix
+	violation Violation
+}
+
+// ApplyFixes applies the first fix of each selected violation. Fixes whose
+// files changed since analysis, or whose edits overlap an earlier fix, are
+// skipped; edited files are formatted with go/format and written, or
+// returned as content for overlay files. In dry-run mode nothing is written
+// and a unified diff is returned for each file.
+func ApplyFixes(request ApplyFixesRequest) (*ApplyFixesResult, error) {
+	result := &ApplyFixesResult{
+		Files:   []FixedFile{},
+		Applied: []FixOutcome{},
+		Skipped: []FixOutcome{},
+		Errors:  []Error{},
+	}
+
+	violations, err := violationsToFix(request)
+	if err != nil {
+		return nil, err
+	}
+
+	overlay := make(map[string]string)
+	for path, content := range request.Options.Overlay {
+		overlay[filepath.Clean(path)] = content
+	}
+	contents := make(map[string][]byte)
+	readContent := func(path string) ([]byte, error) {
+		if content, ok := contents[path]; ok {
+			return content, nil
+		}
+		content, ok := []byte(nil), false
+		if text, inOverlay := overlay[filepath.Clean(path)]; inOverlay {
+			content, ok = []byte(text), true
+		}
+		if !ok {
+			var err error
+			if content, err = os.ReadFile(path); err != nil {
+				return nil, err
+			}
+		}
+		contents[path] = content
+		return content, nil
+	}
+
+	// Accept fixes in order, keeping only those that do not conflict
+	accepted := make(map[string][]TextEdit)
+	var pending []pendingFix
+	for _, violation := range violations {
+		if len(violation.Fixes) == 0 {
+			continue
+		}
+		fix := violation.Fixes[0]
+		if reason := checkFix(fix, readContent, accepted); reason != "" {
+			result.Skipped = append(result.Skipped, fixOutcome(fix, violation, reason))
+			continue
+		}
+		for _, edit := range fix.Edits {
+			if !containsEdit(accepted[edit.File], edit) {
+				accepted[edit.File] = append(accepted[edit.File], edit)
+			}
+		}
+		pending = append(pending, pendingFix{fix: fix, violation: violation})
+	}
+
+	// Apply, format and write each file
+	failed := make(map[string]string)
+	for _, path := range sortedEditFiles(accepted) {
+		before := contents[path]
+		after, err := format.Source(applyEdits(before, accepted[path]))
+		if err != nil {
+			failed[path] = fmt.Sprintf("fixed file does not format: %v", err)
+			result.Errors = append(result.Errors, Error{Message: failed[path], Type: "format", File: path})
+			continue
+		}
+
+		fixed := FixedFile{File: path, Hash: hashContent(after)}
+		_, inOverlay := overlay[filepath.Clean(path)]
+		switch {
+		case request.DryRun:
+			fixed.Diff = unifiedDiff(filepath.ToSlash(path), before, after)
+			fixed.Content = string(after)
+		case inOverlay:
+			fixed.Content = string(after)
+		default:
+			if err := writeFixedFile(path, after); err != nil {
+				failed[path] = fmt.Sprintf("cannot write file: %v", err)
+				result.Errors = append(result.Errors, Error{Message: failed[path], Type: "write", File: path})
+				continue
+			}
+			fixed.Written = true
+		}
+		result.Files = append(result.Files, fixed)
+	}
+
+	for _, item := range pending {
+		reason := ""
+		for _, edit := range item.fix.Edits {
+			if failed[edit.File] != "" {
+				reason = failed[edit.File]
+			}
+		}
+		if reason != "" {
+			result.Skipped = append(result.Skipped, fixOutcome(item.fix, item.violation, reason))
+		} else {
+			result.Applied = append(result.Applied, fixOutcome(item.fix, item.violation, ""))
+		}
+	}
+
+	return result, nil
+}
+
+// violationsToFix returns the request's violations, or analyzes its files
+// and selects the violations with the requested fingerprints
+func violationsToFix(request ApplyFixesRequest) ([]Violation, error) {
+	if len(request.Fingerprints) == 0 {
+		return request.Violations, nil
+	}
+
+	goAnalyzer := NewAnalyzer(request.Options)
+	var analysis *AnalysisResult
+	var err error
+	switch {
+	case len(request.Files) > 0:
+		analysis, err = goAnalyzer.Analyze(request.Files)
+	case len(request.Options.Overlay) > 0:
+		analysis, err = goAnalyzer.AnalyzeOverlay(request.Options.Overlay)
+	default:
+		return nil, fmt.Errorf("fingerprints require files or an overlay to analyze")
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	wanted := make(map[string]bool)
+	for _, fingerprint := range request.Fingerprints {
+		wanted[fingerprint] = true
+	}
+	var violations []Violation
+	for _, violation := range analysis.Violations {
+		if wanted[violation.Fingerprint] {
+			violations = append(violations, violation)
+		}
+	}
+	return violations, nil
+}
+
+// checkFix returns why a fix cannot be applied, or "" when it can
+func checkFix(fix Fix, readContent func(string) ([]byte, error), accepted map[string][]TextEdit) string {
+	if len(fix.Edits) == 0 {
+		return "fix has no edits"
+	}
+	for _, edit := range fix.Edits {
+		content, err := readContent(edit.File)
+		if err != nil {
+			return fmt.Sprintf("cannot read %s: %v", edit.File, err)
+		}
+		if edit.Hash != "" && edit.Hash != hashContent(content) {
+			return fmt.Sprintf("%s changed since it was analyzed", edit.File)
+		}
+		if edit.Offset < 0 || edit.Offset > edit.End || edit.End > len(content) {
+			return fmt.Sprintf("edit range %d-%d is outside %s", edit.Offset, edit.End, edit.File)
+		}
+		for _, other := range accepted[edit.File] {
+			if editsConflict(edit, other) {
+				return fmt.Sprintf("overlaps another fix at offset %d of %s", other.Offset, edit.File)
+			}
+		}
+	}
+	return ""
+}
+
+// editsConflict reports whether two edits touch the same bytes; identical
+// edits, such as two fixes regrouping the same imports, do not conflict
+func editsConflict(a, b TextEdit) bool {
+	if a.Offset == b.Offset && a.End == b.End && a.NewText == b.NewText {
+		return false
+	}
+	if a.Offset == a.End && b.Offset == b.End {
+		return a.Offset == b.Offset
+	}
+	return a.Offset < b.End && b.Offset < a.End ||
+		a.Offset == a.End && a.Offset > b.Offset && a.Offset < b.End ||
+		b.Offset == b.End && b.Offset > a.Offset && b.Offset < a.End
+}
+
+// containsEdit reports whether an identical edit was already accepted
+func containsEdit(edits []TextEdit, edit TextEdit) bool {
+	for _, other := range edits {
+		if other.Offset == edit.Offset && other.End == edit.End && other.NewText == edit.NewText {
+			return true
+		}
+	}
+	return false
+}
+
+// applyEdits applies non-overlapping edits to content, last offset first.
+// At the same offset a replaced range goes before an insertion, so the
+// inserted text lands in front of the replacement in either accept order.
+func applyEdits(content []byte, edits []TextEdit) []byte {
+	sorted := append([]TextEdit(nil), edits...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		if sorted[i].Offset != sorted[j].Offset {
+			return sorted[i].Offset > sorted[j].Offset
+		}
+		return sorted[i].End > sorted[j].End
+	})
+
+	result := append([]byte(nil), content...)
+	for _, edit := range sorted {
+		updated := make([]byte, 0, len(result)-(edit.End-edit.Offset)+len(edit.NewText))
+		updated = append(updated, result[:edit.Offset]...)
+		updated = append(updated, edit.NewText...)
+		updated = append(updated, result[edit.End:]...)
+		result = updated
+	}
+	return result
+}
+
+// writeFixedFile replaces a file's content, keeping its permissions
+func writeFixedFile(path string, content []byte) error {
+	mode := os.FileMode(0644)
+	if info, err := os.Stat(path); err == nil {
+		mode = info.Mode().Perm()
+	}
+	return os.WriteFile(path, content, mode)
+}
+
+// fixOutcome describes an applied or skipped fix
+func fixOutcome(fix Fix, violation Violation, reason string) FixOutcome {
+	return FixOutcome{
+		Description: fix.Description,
+		Fingerprint: violation.Fingerprint,
+		File:        violation.File,
+		Line:        violation.Line,
+		Reason:      reason,
+	}
+}
+
+// sortedEditFiles returns the files with accepted edits in ascending order
+func sortedEditFiles(edits map[string][]TextEdit) []string {
+	files := make([]string, 0, len(edits))
+	for path := range edits {
+		files = append(files, path)
+	}
+	sort.Strings(files)
+	return files
+}
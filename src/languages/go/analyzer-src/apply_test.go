@@ -0,0 +1,164 @@
+package analyzer
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// applySource is the file the fix tests edit
+const applySource = `package sample
+
+func A() int { return 1 }
+
+func B() int { return 2 }
+`
+
+// replaceEdit replaces the first occurrence of old after the given text in
+// applySource
+func replaceEdit(file, after, old, newText string) TextEdit {
+	offset := strings.Index(applySource, after) + len(after)
+	offset += strings.Index(applySource[offset:], old)
+	return TextEdit{
+		File:    file,
+		Offset:  offset,
+		End:     offset + len(old),
+		NewText: newText,
+		Hash:    hashContent([]byte(applySource)),
+	}
+}
+
+// fixViolation wraps edits in a violation carrying one fix
+func fixViolation(description string, edits ...TextEdit) Violation {
+	return Violation{Fixes: []Fix{{Description: description, Edits: edits}}}
+}
+
+// applyToOverlay applies the fixes of violations to applySource in an
+// overlay and returns the result
+func applyToOverlay(t *testing.T, file string, violations ...Violation) *ApplyFixesResult {
+	t.Helper()
+	result, err := ApplyFixes(ApplyFixesRequest{
+		Violations: violations,
+		Options:    AnalysisOptions{Overlay: map[string]string{file: applySource}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return result
+}
+
+// outcomes lists the descriptions of applied or skipped fixes
+func outcomes(fixes []FixOutcome) []string {
+	var descriptions []string
+	for _, fix := range fixes {
+		descriptions = append(descriptions, fix.Description)
+	}
+	return descriptions
+}
+
+func TestApplyFixesInAnyOrder(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "sample.go")
+	result := applyToOverlay(t, file,
+		fixViolation("later edits first",
+			replaceEdit(file, "B() int { return ", "2", "20"),
+			replaceEdit(file, "A() int { return ", "1", "10")),
+		fixViolation("rename A", replaceEdit(file, "func ", "A", "First")),
+	)
+
+	if len(result.Skipped) != 0 || len(result.Applied) != 2 {
+		t.Fatalf("applied %v, skipped %v", outcomes(result.Applied), result.Skipped)
+	}
+	want := strings.NewReplacer("A()", "First()", "return 1", "return 10", "return 2", "return 20").Replace(applySource)
+	if len(result.Files) != 1 || result.Files[0].Content != want {
+		t.Errorf("got files %+v, want content:\n%s", result.Files, want)
+	}
+	if result.Files[0].Written {
+		t.Error("overlay file was written to disk")
+	}
+}
+
+func TestApplyFixesSkipsConflicts(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "sample.go")
+	returnOne := replaceEdit(file, "A() int { ", "return 1", "return 10")
+	result := applyToOverlay(t, file,
+		fixViolation("first", returnOne),
+		fixViolation("overlapping", replaceEdit(file, "A() int { return ", "1", "100")),
+		fixViolation("identical", returnOne),
+		fixViolation("insert inside", TextEdit{File: file, Offset: returnOne.Offset + 1, End: returnOne.Offset + 1, NewText: "x", Hash: returnOne.Hash}),
+	)
+
+	if got := outcomes(result.Applied); strings.Join(got, ",") != "first,identical" {
+		t.Errorf("applied %v, want first and identical", got)
+	}
+	if len(result.Skipped) != 2 || !strings.Contains(result.Skipped[0].Reason, "overlaps") || !strings.Contains(result.Skipped[1].Reason, "overlaps") {
+		t.Errorf("skipped %+v, want the overlapping fixes", result.Skipped)
+	}
+	if want := strings.Replace(applySource, "return 1", "return 10", 1); len(result.Files) != 1 || result.Files[0].Content != want {
+		t.Errorf("got files %+v, want the first fix applied once", result.Files)
+	}
+}
+
+func TestApplyFixesInsertsBeforeReplacedRange(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "sample.go")
+	replace := replaceEdit(file, "A() int { ", "return 1", "return 10")
+	insert := TextEdit{File: file, Offset: replace.Offset, End: replace.Offset, NewText: "_ = 0; ", Hash: replace.Hash}
+
+	// The result must not depend on which fix is accepted first
+	want := strings.Replace(applySource, "return 1", "_ = 0; return 10", 1)
+	for _, order := range [][]Violation{
+		{fixViolation("insert", insert), fixViolation("replace", replace)},
+		{fixViolation("replace", replace), fixViolation("insert", insert)},
+	} {
+		result := applyToOverlay(t, file, order...)
+		if len(result.Applied) != 2 || len(result.Files) != 1 {
+			t.Fatalf("applied %v, skipped %+v", outcomes(result.Applied), result.Skipped)
+		}
+		if got := result.Files[0].Content; !strings.Contains(got, "{ _ = 0; return 10 }") {
+			t.Errorf("order %s,%s: got\n%s\nwant\n%s", order[0].Fixes[0].Description, order[1].Fixes[0].Description, got, want)
+		}
+	}
+}
+
+func TestApplyFixesSkipsChangedFiles(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "sample.go")
+	edit := replaceEdit(file, "A() int { return ", "1", "10")
+	edit.Hash = hashContent([]byte("package sample\n"))
+	result := applyToOverlay(t, file, fixViolation("stale", edit))
+
+	if len(result.Applied) != 0 || len(result.Skipped) != 1 || !strings.Contains(result.Skipped[0].Reason, "changed since it was analyzed") {
+		t.Errorf("applied %v, skipped %+v; want the stale fix skipped", outcomes(result.Applied), result.Skipped)
+	}
+}
+
+func TestApplyFixesWritesFilesAndDiffsDryRuns(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "sample.go")
+	if err := os.WriteFile(file, []byte(applySource), 0600); err != nil {
+		t.Fatal(err)
+	}
+	violation := fixViolation("return 10", replaceEdit(file, "A() int { return ", "1", "10"))
+
+	result, err := ApplyFixes(ApplyFixesRequest{Violations: []Violation{violation}, DryRun: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	wantDiff := "--- a/" + filepath.ToSlash(file) + "\n+++ b/" + filepath.ToSlash(file) + "\n" +
+		"@@ -1,5 +1,5 @@\n package sample\n \n-func A() int { return 1 }\n+func A() int { return 10 }\n \n func B() int { return 2 }\n"
+	if len(result.Files) != 1 || result.Files[0].Diff != wantDiff || result.Files[0].Written {
+		t.Errorf("dry run files %+v, want diff:\n%s", result.Files, wantDiff)
+	}
+	if content, _ := os.ReadFile(file); string(content) != applySource {
+		t.Error("dry run changed the file")
+	}
+
+	if _, err := ApplyFixes(ApplyFixesRequest{Violations: []Violation{violation}}); err != nil {
+		t.Fatal(err)
+	}
+	content, _ := os.ReadFile(file)
+	if string(content) != strings.Replace(applySource, "return 1", "return 10", 1) {
+		t.Errorf("written file:\n%s", content)
+	}
+	if info, err := os.Stat(file); err != nil || info.Mode().Perm() != 0600 {
+		t.Errorf("file mode changed: %v", info.Mode())
+	}
+}
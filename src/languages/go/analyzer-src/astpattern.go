@@ -0,0 +1,453 @@
+package analyzer
+
+import (
+	"bytes"
+	"fmt"
+	"go/ast"
+	goparser "go/parser"
+	"go/printer"
+	"go/token"
+	"go/types"
+	"reflect"
+	"regexp"
+	"strings"
+)
+
+// Metavariables are rewritten to identifiers with these prefixes before the
+// pattern is parsed as Go: $X binds one node, $$$ and $$$X bind any number
+const (
+	metavariablePrefix      = "__auditor_mv_"
+	multiMetavariablePrefix = "__auditor_mvs_"
+)
+
+var (
+	multiMetavariablePattern = regexp.MustCompile(`\$\$\$([A-Z_][A-Z0-9_]*)?`)
+	metavariablePattern      = regexp.MustCompile(`\$([A-Z_][A-Z0-9_]*)`)
+)
+
+// astPattern is a parsed ast-pattern rule: an expression, a statement
+// sequence or a declaration
+type astPattern struct {
+	expr  ast.Expr
+	stmts []ast.Stmt
+	decl  ast.Decl
+}
+
+// parseASTPattern parses pattern source written in Go syntax with metavariables
+func parseASTPattern(source string) (*astPattern, error) {
+	source = multiMetavariablePattern.ReplaceAllString(source, multiMetavariablePrefix+"$1")
+	source = metavariablePattern.ReplaceAllString(source, metavariablePrefix+"$1")
+
+	if expr, err := goparser.ParseExpr(source); err == nil {
+		return &astPattern{expr: expr}, nil
+	}
+
+	fileSet := token.NewFileSet()
+	if file, err := goparser.ParseFile(fileSet, "pattern.go", "package p\nfunc _() {\n"+source+"\n}", 0); err == nil {
+		body := file.Decls[0].(*ast.FuncDecl).Body.List
+		if len(body) > 0 {
+			return &astPattern{stmts: body}, nil
+		}
+	}
+
+	file, err := goparser.ParseFile(fileSet, "pattern.go", "package p\n"+source, 0)
+	if err != nil {
+		return nil, fmt.Errorf("pattern is not a Go expression, statement or declaration: %v", err)
+	}
+	if len(file.Decls) != 1 {
+		return nil, fmt.Errorf("pattern must contain exactly one declaration")
+	}
+	return &astPattern{decl: file.Decls[0]}, nil
+}
+
+// patternMatch is one match of a pattern in a file
+type patternMatch struct {
+	from, to token.Pos
+	nodes    map[string]ast.Node
+	texts    map[string]string
+}
+
+// patternMatcher matches a pattern against the nodes of one file, recording
+// metavariable bindings
+type patternMatcher struct {
+	parser *Parser
+	nodes  map[string]ast.Node
+	texts  map[string]string
+}
+
+// findPatternMatches returns every match of pattern in file
+func (p *Parser) findPatternMatches(pattern *astPattern, file *ast.File) []patternMatch {
+	var matches []patternMatch
+	matcher := &patternMatcher{parser: p}
+
+	record := func(from, to token.Pos) {
+		matches = append(matches, patternMatch{from: from, to: to, nodes: matcher.nodes, texts: matcher.texts})
+	}
+
+	ast.Inspect(file, func(n ast.Node) bool {
+		if n == nil {
+			return false
+		}
+		switch {
+		case pattern.expr != nil:
+			if _, ok := n.(ast.Expr); ok && matcher.matchNode(pattern.expr, n) {
+				record(n.Pos(), n.End())
+			}
+		case pattern.decl != nil:
+			if _, ok := n.(ast.Decl); ok && matcher.matchNode(pattern.decl, n) {
+				record(n.Pos(), n.End())
+			}
+		case len(pattern.stmts) == 1:
+			if _, ok := n.(ast.Stmt); ok && matcher.matchNode(pattern.stmts[0], n) {
+				record(n.Pos(), n.End())
+			}
+		default:
+			// Statement sequences match contiguous runs within a block
+			var list []ast.Stmt
+			switch block := n.(type) {
+			case *ast.BlockStmt:
+				list = block.List
+			case *ast.CaseClause:
+				list = block.Body
+			case *ast.CommClause:
+				list = block.Body
+			}
+			for start := 0; start < len(list); start++ {
+				for end := start + 1; end <= len(list); end++ {
+					matcher.reset()
+					if matcher.matchList(reflect.ValueOf(pattern.stmts), reflect.ValueOf(list[start:end]), 0, 0) {
+						record(list[start].Pos(), list[end-1].End())
+						start = end - 1
+						break
+					}
+				}
+			}
+		}
+		return true
+	})
+
+	return matches
+}
+
+// reset clears the bindings before a new match attempt
+func (m *patternMatcher) reset() {
+	m.nodes = make(map[string]ast.Node)
+	m.texts = make(map[string]string)
+}
+
+// matchNode matches a pattern node against a target node from a fresh state
+func (m *patternMatcher) matchNode(pattern, target ast.Node) bool {
+	m.reset()
+	return m.match(reflect.ValueOf(pattern), reflect.ValueOf(target))
+}
+
+// match compares pattern and target structurally, ignoring positions,
+// comments and resolved objects
+func (m *patternMatcher) match(pattern, target reflect.Value) bool {
+	if pattern.Kind() == reflect.Interface {
+		if pattern.IsNil() || target.IsNil() {
+			return pattern.IsNil() && target.IsNil()
+		}
+		pattern, target = pattern.Elem(), target.Elem()
+	}
+
+	if pattern.Kind() == reflect.Ptr && !pattern.IsNil() && pattern.CanInterface() {
+		if name, ok := metavariableName(pattern.Interface()); ok {
+			node, isNode := target.Interface().(ast.Node)
+			return isNode && !target.IsNil() && m.bind(name, node)
+		}
+	}
+
+	if pattern.Type() != target.Type() {
+		return false
+	}
+
+	switch pattern.Kind() {
+	case reflect.Ptr:
+		if pattern.IsNil() || target.IsNil() {
+			return pattern.IsNil() && target.IsNil()
+		}
+		return m.match(pattern.Elem(), target.Elem())
+	case reflect.Slice:
+		return m.matchList(pattern, target, 0, 0)
+	case reflect.Struct:
+		for i := 0; i < pattern.NumField(); i++ {
+			field := pattern.Type().Field(i)
+			if field.PkgPath != "" || ignoredPatternField(field.Type) {
+				continue
+			}
+			if field.Type == reflect.TypeOf(token.NoPos) {
+				// Only optional positions such as CallExpr.Ellipsis carry meaning
+				if pattern.Field(i).Interface().(token.Pos).IsValid() != target.Field(i).Interface().(token.Pos).IsValid() {
+					return false
+				}
+				continue
+			}
+			if !m.match(pattern.Field(i), target.Field(i)) {
+				return false
+			}
+		}
+		return true
+	default:
+		return pattern.Interface() == target.Interface()
+	}
+}
+
+// matchList matches pattern[pi:] against target[ti:], letting multi
+// metavariables consume any number of elements
+func (m *patternMatcher) matchList(pattern, target reflect.Value, pi, ti int) bool {
+	if pi == pattern.Len() {
+		return ti == target.Len()
+	}
+
+	if name, ok := multiMetavariableName(pattern.Index(pi).Interface()); ok {
+		for end := ti; end <= target.Len(); end++ {
+			nodes, texts := m.snapshot()
+			if m.bindList(name, target, ti, end) && m.matchList(pattern, target, pi+1, end) {
+				return true
+			}
+			m.nodes, m.texts = nodes, texts
+		}
+		return false
+	}
+
+	if ti == target.Len() {
+		return false
+	}
+	nodes, texts := m.snapshot()
+	if m.match(pattern.Index(pi), target.Index(ti)) && m.matchList(pattern, target, pi+1, ti+1) {
+		return true
+	}
+	m.nodes, m.texts = nodes, texts
+	return false
+}
+
+// bind records a single-node metavariable; a repeated name must bind equal code
+func (m *patternMatcher) bind(name string, node ast.Node) bool {
+	text := m.parser.nodeText(node)
+	if name == "_" {
+		return true
+	}
+	if bound, ok := m.texts[name]; ok {
+		return bound == text
+	}
+	m.nodes[name] = node
+	m.texts[name] = text
+	return true
+}
+
+// bindList records a multi metavariable bound to target[from:to]
+func (m *patternMatcher) bindList(name string, target reflect.Value, from, to int) bool {
+	if name == "" || name == "_" {
+		return true
+	}
+	var parts []string
+	for i := from; i < to; i++ {
+		if node, ok := target.Index(i).Interface().(ast.Node); ok {
+			parts = append(parts, m.parser.nodeText(node))
+		}
+	}
+	text := strings.Join(parts, ", ")
+	if bound, ok := m.texts[name]; ok {
+		return bound == text
+	}
+	m.texts[name] = text
+	return true
+}
+
+// snapshot copies the bindings so a failed branch can be undone
+func (m *patternMatcher) snapshot() (map[string]ast.Node, map[string]string) {
+	nodes := make(map[string]ast.Node, len(m.nodes))
+	for key, value := range m.nodes {
+		nodes[key] = value
+	}
+	texts := make(map[string]string, len(m.texts))
+	for key, value := range m.texts {
+		texts[key] = value
+	}
+	return nodes, texts
+}
+
+// ignoredPatternField reports AST fields that never take part in matching
+func ignoredPatternField(fieldType reflect.Type) bool {
+	switch fieldType {
+	case reflect.TypeOf((*ast.Object)(nil)), reflect.TypeOf((*ast.Scope)(nil)), reflect.TypeOf((*ast.CommentGroup)(nil)):
+		return true
+	}
+	return false
+}
+
+// metavariableName returns the name of a single-node metavariable, which may
+// appear as an identifier or as a statement holding one
+func metavariableName(node interface{}) (string, bool) {
+	switch n := node.(type) {
+	case *ast.Ident:
+		if strings.HasPrefix(n.Name, metavariablePrefix) {
+			return strings.TrimPrefix(n.Name, metavariablePrefix), true
+		}
+	case *ast.ExprStmt:
+		if ident, ok := n.X.(*ast.Ident); ok && strings.HasPrefix(ident.Name, metavariablePrefix) {
+			return strings.TrimPrefix(ident.Name, metavariablePrefix), true
+		}
+	}
+	return "", false
+}
+
+// multiMetavariableName returns the name of a multi metavariable list element:
+// an argument, a statement or an unnamed parameter
+func multiMetavariableName(node interface{}) (string, bool) {
+	var ident *ast.Ident
+	switch n := node.(type) {
+	case *ast.Ident:
+		ident = n
+	case *ast.ExprStmt:
+		ident, _ = n.X.(*ast.Ident)
+	case *ast.Field:
+		if len(n.Names) == 0 {
+			ident, _ = n.Type.(*ast.Ident)
+		}
+	}
+	if ident != nil && strings.HasPrefix(ident.Name, multiMetavariablePrefix) {
+		return strings.TrimPrefix(ident.Name, multiMetavariablePrefix), true
+	}
+	return "", false
+}
+
+// nodeText renders a node as Go source
+func (p *Parser) nodeText(node ast.Node) string {
+	var buf bytes.Buffer
+	if err := printer.Fprint(&buf, p.fileSet, node); err != nil {
+		return ""
+	}
+	return buf.String()
+}
+
+// analyzeASTPattern reports code matching the rule's Go pattern whose
+// metavariables satisfy the rule's constraints
+func (r *RuleAnalyzer) analyzeASTPattern(rule Rule) []Violation {
+	var violations []Violation
+	if rule.Language != "go" {
+		// Patterns default to TypeScript syntax, handled by the ast-grep engine
+		return violations
+	}
+	pattern, err := parseASTPattern(rule.Pattern)
+	if err != nil {
+		r.ruleError(rule, fmt.Sprintf("pattern %q cannot be parsed: %v", rule.Pattern, err))
+		return violations
+	}
+	regexes := make(map[string]*regexp.Regexp)
+	for name, constraint := range rule.Constraints {
+		if constraint.Regex == "" {
+			continue
+		}
+		regex, err := regexp.Compile(constraint.Regex)
+		if err != nil {
+			r.ruleError(rule, fmt.Sprintf("constraint %s regex %q is not a valid regular expression: %v", name, constraint.Regex, err))
+			return violations
+		}
+		regexes[name] = regex
+	}
+
+	for filePath, file := range r.parser.files {
+		if rule.Path != "" && !r.parser.matchesFileOrPackage(rule.Path, filePath) {
+			continue
+		}
+
+		for _, match := range r.parser.findPatternMatches(pattern, file) {
+			if !r.satisfiesConstraints(rule, regexes, filePath, match) {
+				continue
+			}
+
+			matched := string(r.parser.sources[filePath][r.parser.fileSet.Position(match.from).Offset:r.parser.fileSet.Position(match.to).Offset])
+			violation := r.ruleViolation(rule, filePath,
+				fmt.Sprintf("AST pattern matched: \"%s\"", matched),
+				map[string]interface{}{
+					"pattern":  rule.Pattern,
+					"bindings": match.texts,
+				})
+			// Messages may refer to bound metavariables as $NAME
+			violation.Message = metavariablePattern.ReplaceAllStringFunc(violation.Message, func(ref string) string {
+				if text, ok := match.texts[ref[1:]]; ok {
+					return text
+				}
+				return ref
+			})
+			violations = append(violations, r.parser.withRange(violation, match.from, match.to))
+		}
+	}
+
+	return violations
+}
+
+// satisfiesConstraints checks the metavariable constraints of a rule, with
+// the constraint regexes compiled by constraint name; type constraints fail
+// when the file cannot be type-checked
+func (r *RuleAnalyzer) satisfiesConstraints(rule Rule, regexes map[string]*regexp.Regexp, filePath string, match patternMatch) bool {
+	for key, constraint := range rule.Constraints {
+		name := strings.TrimPrefix(key, "$")
+		text, bound := match.texts[name]
+		if !bound {
+			return false
+		}
+		if regex := regexes[key]; regex != nil && !regex.MatchString(text) {
+			return false
+		}
+		if constraint.Type == "" && constraint.Implements == "" {
+			continue
+		}
+
+		expr, ok := match.nodes[name].(ast.Expr)
+		typeInfo := r.parser.TypeInfo(filePath)
+		if !ok || typeInfo == nil || typeInfo.Pkg == nil {
+			return false
+		}
+		exprType := typeInfo.Info.TypeOf(expr)
+		if exprType == nil {
+			return false
+		}
+		if constraint.Type != "" && !typeNameMatches(exprType, constraint.Type) {
+			return false
+		}
+		if constraint.Implements != "" {
+			iface := lookupInterface(typeInfo.Pkg, constraint.Implements)
+			if iface == nil || !types.Implements(exprType, iface) {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// typeNameMatches compares a type with a name written with either package
+// names (*sql.DB) or full import paths (*database/sql.DB)
+func typeNameMatches(t types.Type, name string) bool {
+	byName := types.TypeString(t, func(pkg *types.Package) string { return pkg.Name() })
+	return byName == name || types.TypeString(t, nil) == name
+}
+
+// lookupInterface resolves "error" or "pkg.Name" (by package name or import
+// path) to an interface visible from pkg
+func lookupInterface(pkg *types.Package, name string) *types.Interface {
+	var obj types.Object
+	if index := strings.LastIndex(name, "."); index < 0 {
+		obj = types.Universe.Lookup(name)
+		if obj == nil {
+			obj = pkg.Scope().Lookup(name)
+		}
+	} else {
+		pkgName, typeName := name[:index], name[index+1:]
+		candidates := append([]*types.Package{pkg}, pkg.Imports()...)
+		for _, candidate := range candidates {
+			if candidate.Path() == pkgName || candidate.Name() == pkgName {
+				obj = candidate.Scope().Lookup(typeName)
+				break
+			}
+		}
+	}
+
+	if obj == nil {
+		return nil
+	}
+	iface, _ := obj.Type().Underlying().(*types.Interface)
+	return iface
+}
@@ -0,0 +1,163 @@
+package analyzer
+
+import (
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// patternSource is the file every ast-pattern case is matched against
+const patternSource = `package sample
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+type name string
+
+func (n name) String() string { return string(n) }
+
+func wrap(err error, db *sql.DB) error {
+	go func() {
+		db.Close()
+	}()
+	go db.Close()
+	if err != nil {
+		return fmt.Errorf("wrap: %v", err)
+	}
+	fmt.Errorf("count: %d", 3)
+	fmt.Println(name("n"), strings.ToUpper("s"))
+	same := errors.New("same")
+	errors.Is(same, same)
+	errors.Is(err, same)
+	return fmt.Errorf("twice: %v %v", err, err)
+}
+`
+
+// patternMatches returns the source text of every match of a Go ast-pattern
+// rule in patternSource, and the errors of the run
+func patternMatches(t *testing.T, rule Rule) ([]string, []Error) {
+	t.Helper()
+	rule.ID, rule.Kind, rule.Language = "pattern", RuleASTPattern, "go"
+	result := analyzeWritten(t, filepath.Join(t.TempDir(), "sample.go"), patternSource,
+		AnalysisOptions{Analyzers: []string{"invariants"}, Rules: []Rule{rule}})
+
+	var matches []string
+	for _, violation := range result.Violations {
+		matches = append(matches, strings.TrimSuffix(strings.TrimPrefix(violation.Message, `AST pattern matched: "`), `"`))
+	}
+	return matches, result.Errors
+}
+
+func TestASTPatternMatches(t *testing.T) {
+	tests := []struct {
+		name        string
+		pattern     string
+		constraints map[string]MetavariableConstraint
+		want        []string
+	}{
+		{
+			name:    "call with metavariable arguments",
+			pattern: "fmt.Errorf($FMT, $ERR)",
+			want:    []string{`fmt.Errorf("wrap: %v", err)`, `fmt.Errorf("count: %d", 3)`},
+		},
+		{
+			name:    "statement with a multi metavariable body",
+			pattern: "go func() { $$$ }()",
+			want:    []string{"go func() {\n\t\tdb.Close()\n\t}()"},
+		},
+		{
+			name:    "repeated metavariable binds the same code",
+			pattern: "errors.Is($X, $X)",
+			want:    []string{"errors.Is(same, same)"},
+		},
+		{
+			name:    "multi metavariable binds any arguments",
+			pattern: "fmt.Errorf($$$ARGS)",
+			want:    []string{`fmt.Errorf("wrap: %v", err)`, `fmt.Errorf("count: %d", 3)`, `fmt.Errorf("twice: %v %v", err, err)`},
+		},
+		{
+			name:        "type constraint",
+			pattern:     "fmt.Errorf($FMT, $ERR)",
+			constraints: map[string]MetavariableConstraint{"$ERR": {Type: "error"}},
+			want:        []string{`fmt.Errorf("wrap: %v", err)`},
+		},
+		{
+			name:        "type constraint with an import path",
+			pattern:     "go $X.Close()",
+			constraints: map[string]MetavariableConstraint{"$X": {Type: "*database/sql.DB"}},
+			want:        []string{"go db.Close()"},
+		},
+		{
+			name:        "implements constraint",
+			pattern:     "fmt.Println($A, $B)",
+			constraints: map[string]MetavariableConstraint{"$A": {Implements: "fmt.Stringer"}},
+			want:        []string{`fmt.Println(name("n"), strings.ToUpper("s"))`},
+		},
+		{
+			name:        "failed implements constraint",
+			pattern:     "fmt.Println($A, $B)",
+			constraints: map[string]MetavariableConstraint{"$B": {Implements: "fmt.Stringer"}},
+		},
+		{
+			name:        "regex constraint",
+			pattern:     "fmt.Errorf($FMT, $$$)",
+			constraints: map[string]MetavariableConstraint{"$FMT": {Regex: `^"(wrap|twice)`}},
+			want:        []string{`fmt.Errorf("wrap: %v", err)`, `fmt.Errorf("twice: %v %v", err, err)`},
+		},
+		{
+			name:        "constraint on an unbound metavariable",
+			pattern:     "fmt.Errorf($FMT, $ERR)",
+			constraints: map[string]MetavariableConstraint{"$OTHER": {Regex: "."}},
+		},
+	}
+
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			matches, errors := patternMatches(t, Rule{Pattern: test.pattern, Constraints: test.constraints})
+			if len(errors) != 0 {
+				t.Fatalf("unexpected errors %+v", errors)
+			}
+			if !reflect.DeepEqual(matches, test.want) {
+				t.Errorf("got %q, want %q", matches, test.want)
+			}
+		})
+	}
+}
+
+func TestASTPatternErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		rule Rule
+		want string
+	}{
+		{
+			name: "unparsable pattern",
+			rule: Rule{Pattern: "fmt.Errorf($FMT,"},
+			want: `Rule "pattern": pattern "fmt.Errorf($FMT," cannot be parsed: pattern is not a Go expression, statement or declaration`,
+		},
+		{
+			name: "invalid constraint regex",
+			rule: Rule{Pattern: "fmt.Errorf($FMT)", Constraints: map[string]MetavariableConstraint{"$FMT": {Regex: "("}}},
+			want: `Rule "pattern": constraint $FMT regex "(" is not a valid regular expression`,
+		},
+	}
+
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			matches, errors := patternMatches(t, test.rule)
+			if len(matches) != 0 {
+				t.Errorf("unexpected matches %q", matches)
+			}
+			if len(errors) != 1 || errors[0].Type != "rule" || !strings.HasPrefix(errors[0].Message, test.want) {
+				t.Errorf("errors %+v, want one starting with %q", errors, test.want)
+			}
+		})
+	}
+}
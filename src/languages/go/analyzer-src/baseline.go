@@ -0,0 +1,119 @@
+package analyzer
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// baselineVersion is the version written to new baseline files
+const baselineVersion = 1
+
+// NewBaseline records the given violations as accepted findings
+func (a *Analyzer) NewBaseline(violations []Violation) Baseline {
+	baseline := Baseline{
+		Version:      baselineVersion,
+		Fingerprints: []BaselineEntry{},
+	}
+	for _, violation := range violations {
+		baseline.Fingerprints = append(baseline.Fingerprints, a.baselineEntry(violation))
+	}
+	return baseline
+}
+
+// LoadBaseline reads a baseline file
+func LoadBaseline(path string) (*Baseline, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var baseline Baseline
+	if err := json.Unmarshal(content, &baseline); err != nil {
+		return nil, fmt.Errorf("invalid baseline %s: %v", path, err)
+	}
+	return &baseline, nil
+}
+
+// WriteBaseline writes a baseline file
+func WriteBaseline(path string, baseline Baseline) error {
+	content, err := json.MarshalIndent(baseline, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, append(content, '\n'), 0644)
+}
+
+// applyBaseline drops violations recorded in the configured baseline and
+// lists baseline entries of the analyzed files that no longer occur.
+// Fingerprints are counted, so a second identical finding is still new.
+func (a *Analyzer) applyBaseline(result *AnalysisResult) {
+	if a.options.Baseline == "" {
+		return
+	}
+	baseline, err := LoadBaseline(a.options.Baseline)
+	if err != nil {
+		result.Errors = append(result.Errors, Error{
+			Message: fmt.Sprintf("Baseline not applied: %v", err),
+			Type:    "baseline",
+			File:    a.options.Baseline,
+		})
+		return
+	}
+
+	remaining := make(map[string]int)
+	for _, entry := range baseline.Fingerprints {
+		remaining[entry.Fingerprint]++
+	}
+
+	newViolations := []Violation{}
+	for _, violation := range result.Violations {
+		if remaining[violation.Fingerprint] > 0 {
+			remaining[violation.Fingerprint]--
+			continue
+		}
+		newViolations = append(newViolations, violation)
+	}
+	result.Violations = newViolations
+
+	// Only files that were analyzed can tell whether a finding was fixed
+	analyzed := make(map[string]bool)
+	for filePath := range a.parser.files {
+		if !a.parser.IsContextFile(filePath) {
+			analyzed[a.baselinePath(filePath)] = true
+		}
+	}
+	result.FixedSinceBaseline = []BaselineEntry{}
+	for _, entry := range baseline.Fingerprints {
+		if analyzed[entry.File] && remaining[entry.Fingerprint] > 0 {
+			remaining[entry.Fingerprint]--
+			result.FixedSinceBaseline = append(result.FixedSinceBaseline, entry)
+		}
+	}
+}
+
+// baselineEntry describes a violation for a baseline file
+func (a *Analyzer) baselineEntry(violation Violation) BaselineEntry {
+	fingerprint := violation.Fingerprint
+	if fingerprint == "" {
+		fingerprint = a.parser.fingerprint(violation)
+	}
+	return BaselineEntry{
+		Fingerprint: fingerprint,
+		File:        a.baselinePath(violation.File),
+		Line:        violation.Line,
+		Analyzer:    violation.Analyzer,
+		Category:    violation.Category,
+		Severity:    violation.Severity,
+		Message:     violation.Message,
+	}
+}
+
+// baselinePath names a file relative to its root, or absolutely when it has
+// none, so baselines do not depend on how files were passed in
+func (a *Analyzer) baselinePath(filePath string) string {
+	if absPath, err := filepath.Abs(filePath); err == nil {
+		filePath = absPath
+	}
+	return a.parser.relativePath(filePath)
+}
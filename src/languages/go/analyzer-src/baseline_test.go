@@ -0,0 +1,162 @@
+package analyzer
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+// analyzeWritten writes source to filePath and analyzes it
+func analyzeWritten(t *testing.T, filePath, source string, options AnalysisOptions) *AnalysisResult {
+	t.Helper()
+	if err := os.WriteFile(filePath, []byte(source), 0644); err != nil {
+		t.Fatal(err)
+	}
+	result, err := NewAnalyzer(options).Analyze([]string{filePath})
+	if err != nil {
+		t.Fatalf("analysis failed: %v", err)
+	}
+	return result
+}
+
+// writeBaselineOf records the violations of source as a baseline file
+func writeBaselineOf(t *testing.T, dir, source string, options AnalysisOptions) string {
+	t.Helper()
+	analyzer := NewAnalyzer(options)
+	filePath := filepath.Join(dir, "sample.go")
+	if err := os.WriteFile(filePath, []byte(source), 0644); err != nil {
+		t.Fatal(err)
+	}
+	result, err := analyzer.Analyze([]string{filePath})
+	if err != nil {
+		t.Fatalf("analysis failed: %v", err)
+	}
+	baselinePath := filepath.Join(dir, "baseline.json")
+	if err := WriteBaseline(baselinePath, analyzer.NewBaseline(result.Violations)); err != nil {
+		t.Fatal(err)
+	}
+	return baselinePath
+}
+
+func TestBaselineHidesRecordedFindings(t *testing.T) {
+	dir := t.TempDir()
+	options := AnalysisOptions{Analyzers: []string{"naming"}}
+	options.Baseline = writeBaselineOf(t, dir, `package sample
+
+var snake_case = 1
+
+var other_case = 2
+`, options)
+
+	// Lines shift and are reformatted; other_case is fixed and new_case added
+	result := analyzeWritten(t, filepath.Join(dir, "sample.go"), `package sample
+
+// Values used by the sample.
+
+var   snake_case   = 1
+
+var new_case = 3
+`, options)
+
+	var reported []string
+	for _, violation := range result.Violations {
+		reported = append(reported, violation.Details["name"].(string))
+	}
+	if want := []string{"new_case"}; !reflect.DeepEqual(reported, want) {
+		t.Errorf("reported %v, want %v", reported, want)
+	}
+
+	if len(result.FixedSinceBaseline) != 1 || result.FixedSinceBaseline[0].Line != 5 {
+		t.Fatalf("fixed since baseline %+v, want the finding on line 5", result.FixedSinceBaseline)
+	}
+	if fixed := result.FixedSinceBaseline[0]; fixed.File != filepath.ToSlash(filepath.Join(dir, "sample.go")) || fixed.Category != "mixed-caps" {
+		t.Errorf("fixed entry %+v", fixed)
+	}
+}
+
+func TestBaselineKeepsImportBlockFinding(t *testing.T) {
+	dir := t.TempDir()
+	options := AnalysisOptions{
+		Analyzers: []string{"imports"},
+		Settings:  map[string]AnalyzerSettings{"imports": {Thresholds: map[string]int{"importCount": 1}}},
+	}
+	options.Baseline = writeBaselineOf(t, dir, `package sample
+
+import (
+	"fmt"
+	"os"
+)
+
+var _ = fmt.Sprint
+var _ = os.Exit
+`, options)
+
+	// Another import inside the block must not turn the finding into a new one
+	result := analyzeWritten(t, filepath.Join(dir, "sample.go"), `package sample
+
+import (
+	"fmt"
+	"os"
+	"strings"
+)
+
+var _ = fmt.Sprint
+var _ = os.Exit
+var _ = strings.Cut
+`, options)
+
+	if len(result.Violations) != 0 || len(result.FixedSinceBaseline) != 0 {
+		t.Errorf("got violations %+v and fixed %+v, want the import-count finding matched", result.Violations, result.FixedSinceBaseline)
+	}
+}
+
+func TestBaselineCountsIdenticalFindings(t *testing.T) {
+	dir := t.TempDir()
+	options := AnalysisOptions{Analyzers: []string{"naming"}}
+	options.Baseline = writeBaselineOf(t, dir, `package sample
+
+var snake_case = 1
+`, options)
+
+	content, err := os.ReadFile(options.Baseline)
+	if err != nil {
+		t.Fatal(err)
+	}
+	baseline, err := LoadBaseline(options.Baseline)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(baseline.Fingerprints) != 1 || baseline.Fingerprints[0].Fingerprint == "" {
+		t.Fatalf("baseline %s, want one fingerprinted entry", content)
+	}
+
+	// The same fingerprint recorded once only covers one occurrence
+	baseline.Fingerprints = append(baseline.Fingerprints, baseline.Fingerprints[0])
+	if err := WriteBaseline(options.Baseline, *baseline); err != nil {
+		t.Fatal(err)
+	}
+	result := analyzeWritten(t, filepath.Join(dir, "sample.go"), `package sample
+
+var snake_case = 1
+`, options)
+	if len(result.Violations) != 0 || len(result.FixedSinceBaseline) != 1 {
+		t.Errorf("got %d violations and %d fixed, want 0 and 1", len(result.Violations), len(result.FixedSinceBaseline))
+	}
+}
+
+func TestBaselineLoadError(t *testing.T) {
+	dir := t.TempDir()
+	options := AnalysisOptions{Analyzers: []string{"naming"}, Baseline: filepath.Join(dir, "missing.json")}
+	result := analyzeWritten(t, filepath.Join(dir, "sample.go"), `package sample
+
+var snake_case = 1
+`, options)
+
+	if len(result.Violations) != 1 {
+		t.Errorf("got %d violations, want the finding reported without a baseline", len(result.Violations))
+	}
+	if len(result.Errors) != 1 || result.Errors[0].Type != "baseline" {
+		t.Errorf("errors %+v, want one baseline error", result.Errors)
+	}
+}
@@ -0,0 +1,64 @@
+package analyzer
+
+import (
+	"fmt"
+	"path/filepath"
+)
+
+// analyzeModuleBoundary reports imports from packages matching rule.From
+// into packages matching rule.To. Targets match by import path or, for
+// local packages, by their directory relative to the root.
+func (r *RuleAnalyzer) analyzeModuleBoundary(rule Rule) []Violation {
+	var violations []Violation
+	if rule.From == "" || rule.To == "" {
+		return violations
+	}
+
+	for filePath, file := range r.parser.files {
+		if !r.parser.matchesFileOrPackage(rule.From, filePath) || r.isBoundaryException(rule, filePath, "") {
+			continue
+		}
+
+		for _, importSpec := range file.Imports {
+			importPath := importPathOf(importSpec)
+			if !r.importMatchesTarget(rule.To, filePath, importPath) || r.isBoundaryException(rule, filePath, importPath) {
+				continue
+			}
+
+			violations = append(violations, r.parser.withRange(r.ruleViolation(rule, filePath,
+				fmt.Sprintf("Import of %s crosses the module boundary %s -> %s", importPath, rule.From, rule.To),
+				map[string]interface{}{
+					"importSpecifier": importPath,
+					"from":            rule.From,
+					"to":              rule.To,
+				}), importSpec.Path.Pos(), importSpec.Path.End()))
+		}
+	}
+
+	return violations
+}
+
+// importMatchesTarget reports whether an import from filePath lands in the target glob
+func (r *RuleAnalyzer) importMatchesTarget(pattern, filePath, importPath string) bool {
+	if matchGlob(pattern, importPath) {
+		return true
+	}
+	if dir, ok := r.parser.ResolveImportDir(filepath.Dir(filePath), importPath); ok {
+		return matchGlob(pattern, r.parser.relativePath(dir))
+	}
+	return false
+}
+
+// isBoundaryException reports whether the importing file or package, or the
+// imported path, is listed in the rule's exceptions
+func (r *RuleAnalyzer) isBoundaryException(rule Rule, filePath, importPath string) bool {
+	for _, pattern := range rule.Except {
+		if importPath == "" && r.parser.matchesFileOrPackage(pattern, filePath) {
+			return true
+		}
+		if importPath != "" && matchGlob(pattern, importPath) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,386 @@
+package analyzer
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"go/ast"
+	goparser "go/parser"
+	"go/token"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// cacheEntry is the cached analysis of one file
+type cacheEntry struct {
+	Violations   []Violation           `json:"violations"`
+	Suppressed   []SuppressedViolation `json:"suppressed"`
+	IndexEntries []IndexEntry          `json:"indexEntries"`
+}
+
+// analysisCache stores cache entries as JSON files below a directory, one
+// per key, sharded by the first two characters of the key
+type analysisCache struct {
+	dir string
+}
+
+// path returns the file of a cache key
+func (c *analysisCache) path(key string) string {
+	return filepath.Join(c.dir, key[:2], key+".json")
+}
+
+// load returns the entry stored under key; a hit refreshes the entry's
+// modification time so pruning keeps entries that are still in use
+func (c *analysisCache) load(key string) (*cacheEntry, bool) {
+	path := c.path(key)
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, false
+	}
+	var entry cacheEntry
+	if err := json.Unmarshal(content, &entry); err != nil {
+		return nil, false
+	}
+	now := time.Now()
+	_ = os.Chtimes(path, now, now)
+	return &entry, true
+}
+
+// store writes an entry under key, replacing the file atomically
+func (c *analysisCache) store(key string, entry *cacheEntry) error {
+	content, err := json.Marshal(entry)
+	if err != nil {
+		return err
+	}
+	path := c.path(key)
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	temp, err := os.CreateTemp(filepath.Dir(path), key+".*.tmp")
+	if err != nil {
+		return err
+	}
+	if _, err := temp.Write(content); err != nil {
+		temp.Close()
+		os.Remove(temp.Name())
+		return err
+	}
+	if err := temp.Close(); err != nil {
+		os.Remove(temp.Name())
+		return err
+	}
+	return os.Rename(temp.Name(), path)
+}
+
+// PruneCache removes the entries of a cache directory that were not used
+// within maxAge, along with leftover temporary files
+func PruneCache(dir string, maxAge time.Duration) (CachePruneResult, error) {
+	var result CachePruneResult
+	cutoff := time.Now().Add(-maxAge)
+
+	err := filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if info.IsDir() {
+			return nil
+		}
+		if !strings.HasSuffix(path, ".json") && !strings.HasSuffix(path, ".tmp") {
+			return nil
+		}
+		if info.ModTime().After(cutoff) {
+			result.Kept++
+			return nil
+		}
+		if err := os.Remove(path); err != nil {
+			return err
+		}
+		result.Removed++
+		result.RemovedBytes += info.Size()
+		return nil
+	})
+	return result, err
+}
+
+// analyzeCached analyzes the parsed files, reusing cached results for
+// packages whose files, imported packages and configuration are unchanged.
+// Packages that must be analyzed again are checked with the packages they
+// import loaded as context, so facts and types stay complete; every other
+// cached package is not analyzed at all.
+func (a *Analyzer) analyzeCached(fileCount int, startTime time.Time) *AnalysisResult {
+	cache := &analysisCache{dir: a.options.CacheDir}
+	keys := a.cacheKeys()
+	packages, modules := a.parser.PackageAndModuleCounts()
+
+	// A package is analyzed again when any of its files missed
+	hits := make(map[string]*cacheEntry)
+	missed := make(map[packageKey]bool)
+	for filePath, key := range keys {
+		if entry, ok := cache.load(key); ok {
+			hits[filePath] = entry
+		} else {
+			missed[packageKeyFor(filePath, a.parser.files[filePath])] = true
+		}
+	}
+
+	needed := a.parser.importClosure(missed)
+	for filePath, file := range a.parser.files {
+		pkg := packageKeyFor(filePath, file)
+		switch {
+		case missed[pkg]:
+			delete(hits, filePath)
+		case needed[pkg]:
+			a.parser.contextFiles[filePath] = true
+		default:
+			delete(a.parser.files, filePath)
+			delete(a.parser.sources, filePath)
+		}
+	}
+	a.parser.invalidate()
+
+	result := a.collect(fileCount)
+
+	// Store the fresh results of every analyzed file, findings or not
+	fresh := make(map[string]*cacheEntry)
+	for filePath := range keys {
+		if _, hit := hits[filePath]; !hit {
+			fresh[filePath] = &cacheEntry{Violations: []Violation{}, Suppressed: []SuppressedViolation{}, IndexEntries: []IndexEntry{}}
+		}
+	}
+	for _, violation := range result.Violations {
+		if entry, ok := fresh[violation.File]; ok {
+			entry.Violations = append(entry.Violations, violation)
+		}
+	}
+	for _, suppressed := range result.Suppressed {
+		if entry, ok := fresh[suppressed.File]; ok {
+			entry.Suppressed = append(entry.Suppressed, suppressed)
+		}
+	}
+	for _, indexEntry := range result.IndexEntries {
+		if entry, ok := fresh[indexEntry.File]; ok {
+			entry.IndexEntries = append(entry.IndexEntries, indexEntry)
+		}
+	}
+	for _, filePath := range sortedCacheFiles(fresh) {
+		if err := cache.store(keys[filePath], fresh[filePath]); err != nil {
+			result.Errors = append(result.Errors, Error{
+				Message: fmt.Sprintf("Cannot write cache entry: %v", err),
+				Type:    "cache",
+				File:    filePath,
+			})
+			break
+		}
+	}
+
+	for _, filePath := range sortedCacheFiles(hits) {
+		result.Violations = append(result.Violations, hits[filePath].Violations...)
+		result.Suppressed = append(result.Suppressed, hits[filePath].Suppressed...)
+		result.IndexEntries = append(result.IndexEntries, hits[filePath].IndexEntries...)
+	}
+	result.Metrics.CacheHits = int64(len(hits))
+	result.Metrics.CacheMisses = int64(len(fresh))
+
+	a.finish(result, startTime)
+
+	// Cached packages were not loaded but still count as analyzed
+	result.Metrics.PackagesAnalyzed = int64(packages)
+	result.Metrics.ModulesAnalyzed = int64(modules)
+	return result
+}
+
+// cacheKeys returns the cache key of each reported file: a hash of the
+// analyzer build, the effective configuration, the file's path, its module
+// file and the contents of its package and every package it imports,
+// directly or not, whether analyzed or type-checked from local source
+func (a *Analyzer) cacheKeys() map[string]string {
+	groups := a.parser.packageFiles()
+	config := a.cacheConfig()
+	moduleHashes := make(map[string]string)
+	closureHashes := make(map[packageKey]string)
+
+	keys := make(map[string]string)
+	for filePath, file := range a.parser.files {
+		if a.parser.IsContextFile(filePath) {
+			continue
+		}
+
+		pkg := packageKeyFor(filePath, file)
+		closureHash, ok := closureHashes[pkg]
+		if !ok {
+			closure := a.parser.importClosure(map[packageKey]bool{pkg: true})
+			var closureFiles []string
+			for member := range closure {
+				closureFiles = append(closureFiles, groups[member]...)
+			}
+			sort.Strings(closureFiles)
+
+			hash := sha256.New()
+			for _, member := range closureFiles {
+				fmt.Fprintf(hash, "%s\x00%s\x00", member, a.parser.contentHash(member))
+			}
+			loaded := a.parser.localImportHashes(closure)
+			loadedFiles := make([]string, 0, len(loaded))
+			for member := range loaded {
+				loadedFiles = append(loadedFiles, member)
+			}
+			sort.Strings(loadedFiles)
+			for _, member := range loadedFiles {
+				fmt.Fprintf(hash, "%s\x00%s\x00", member, loaded[member])
+			}
+			closureHash = hex.EncodeToString(hash.Sum(nil))
+			closureHashes[pkg] = closureHash
+		}
+
+		moduleHash := ""
+		if module := a.parser.ModuleFor(filePath); module != nil {
+			if moduleHash, ok = moduleHashes[module.Dir]; !ok {
+				if content, err := os.ReadFile(filepath.Join(module.Dir, "go.mod")); err == nil {
+					moduleHash = hashContent(content)
+				}
+				moduleHashes[module.Dir] = moduleHash
+			}
+		}
+
+		keys[filePath] = hashContent([]byte(strings.Join([]string{
+			Version, buildID(), config, filePath, moduleHash, closureHash,
+		}, "\x00")))
+	}
+
+	return keys
+}
+
+// cacheConfig serializes the options that shape per-file results; the
+// baseline, change scope and severity filter are applied after the cache
+func (a *Analyzer) cacheConfig() string {
+	options := a.options
+	options.Overlay = nil
+	options.Baseline = ""
+	options.MinSeverity = ""
+	options.CacheDir = ""
+	options.Timeout = 0
+	options.Verbose = false
+	options.Diff = ""
+	options.Changes = nil
+	content, _ := json.Marshal(options)
+	return string(content)
+}
+
+// importClosure returns the given packages and every parsed package they
+// import, directly or not
+func (p *Parser) importClosure(roots map[packageKey]bool) map[packageKey]bool {
+	groups := p.packageFiles()
+	byImportPath := make(map[string]packageKey)
+	for key, filePaths := range groups {
+		if _, taken := byImportPath[p.ImportPathFor(filePaths[0])]; !taken || !strings.HasSuffix(key.Name, "_test") {
+			byImportPath[p.ImportPathFor(filePaths[0])] = key
+		}
+	}
+
+	closure := make(map[packageKey]bool)
+	var visit func(key packageKey)
+	visit = func(key packageKey) {
+		if closure[key] {
+			return
+		}
+		closure[key] = true
+		for _, filePath := range groups[key] {
+			for _, importSpec := range p.files[filePath].Imports {
+				importPath, err := strconv.Unquote(importSpec.Path.Value)
+				if err != nil {
+					continue
+				}
+				if imported, ok := byImportPath[importPath]; ok {
+					visit(imported)
+				}
+			}
+		}
+	}
+	for key := range roots {
+		visit(key)
+	}
+	return closure
+}
+
+// localImportHashes returns the content hashes of the files the
+// localImporter type-checks for the closure: local packages that are not
+// analyzed but imported by the closure, directly or through each other.
+// Their exported API shapes the type information of the closure.
+func (p *Parser) localImportHashes(closure map[packageKey]bool) map[string]string {
+	groups := p.packageFiles()
+	analyzed := make(map[string]bool)
+	for _, filePaths := range groups {
+		analyzed[p.ImportPathFor(filePaths[0])] = true
+	}
+
+	hashes := make(map[string]string)
+	visited := make(map[string]bool)
+	var visit func(fromDir string, imports []*ast.ImportSpec)
+	visit = func(fromDir string, imports []*ast.ImportSpec) {
+		for _, importSpec := range imports {
+			importPath, err := strconv.Unquote(importSpec.Path.Value)
+			if err != nil || analyzed[importPath] || visited[importPath] {
+				continue
+			}
+			visited[importPath] = true
+			dir, ok := p.ResolveImportDir(fromDir, importPath)
+			if !ok {
+				continue
+			}
+			filePaths, _ := localPackageFiles(dir)
+			for _, filePath := range filePaths {
+				src, err := p.localSource(filePath)
+				if err != nil {
+					continue
+				}
+				hashes[filePath] = hashContent(src)
+				if file, err := goparser.ParseFile(token.NewFileSet(), filePath, src, goparser.ImportsOnly); err == nil {
+					visit(dir, file.Imports)
+				}
+			}
+		}
+	}
+	for key := range closure {
+		for _, filePath := range groups[key] {
+			visit(filepath.Dir(filePath), p.files[filePath].Imports)
+		}
+	}
+	return hashes
+}
+
+// buildIDOnce guards buildIDValue
+var (
+	buildIDOnce  sync.Once
+	buildIDValue string
+)
+
+// buildID identifies the running analyzer binary by the hash of its
+// executable, so a rebuilt analyzer does not reuse an older build's results
+func buildID() string {
+	buildIDOnce.Do(func() {
+		executable, err := os.Executable()
+		if err != nil {
+			return
+		}
+		if content, err := os.ReadFile(executable); err == nil {
+			buildIDValue = hashContent(content)
+		}
+	})
+	return buildIDValue
+}
+
+// sortedCacheFiles returns the files of a cache entry map in ascending order
+func sortedCacheFiles(entries map[string]*cacheEntry) []string {
+	files := make([]string, 0, len(entries))
+	for filePath := range entries {
+		files = append(files, filePath)
+	}
+	sort.Strings(files)
+	return files
+}
@@ -0,0 +1,200 @@
+package analyzer
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"testing"
+)
+
+// cacheModule is a module where package a imports package b and package c
+// stands alone; every file has one naming finding
+var cacheModule = map[string]string{
+	"go.mod":  "module example.com/m\n\ngo 1.19\n",
+	"a/a.go":  "package a\n\nimport \"example.com/m/b\"\n\nvar a_value = b.Value\n",
+	"a/a2.go": "package a\n\nvar a_other = 1\n",
+	"b/b.go":  "package b\n\nvar Value = 1\n\nvar b_value = 2\n",
+	"c/c.go":  "package c\n\nvar c_value = 3\n",
+}
+
+// writeCacheModule writes cacheModule below dir and returns its Go files
+func writeCacheModule(t *testing.T, dir string) []string {
+	t.Helper()
+	var files []string
+	for name, content := range cacheModule {
+		filePath := filepath.Join(dir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(filePath), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(filePath, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+		if filepath.Ext(name) == ".go" {
+			files = append(files, filePath)
+		}
+	}
+	sort.Strings(files)
+	return files
+}
+
+// writeFile replaces the content of filePath
+func writeFile(t *testing.T, filePath, content string) {
+	t.Helper()
+	if err := os.WriteFile(filePath, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// cacheRun is what one cached analysis reported
+type cacheRun struct {
+	hits, misses int64
+	names        []string
+}
+
+// analyzeWithCache analyzes files with options and summarizes the result
+func analyzeWithCache(t *testing.T, files []string, options AnalysisOptions) cacheRun {
+	t.Helper()
+	result, err := NewAnalyzer(options).Analyze(files)
+	if err != nil {
+		t.Fatalf("analysis failed: %v", err)
+	}
+	if len(result.Errors) != 0 {
+		t.Fatalf("analysis errors: %+v", result.Errors)
+	}
+	run := cacheRun{hits: result.Metrics.CacheHits, misses: result.Metrics.CacheMisses}
+	for _, violation := range result.Violations {
+		if name, ok := violation.Details["name"].(string); ok {
+			run.names = append(run.names, name)
+		}
+	}
+	sort.Strings(run.names)
+	return run
+}
+
+func TestCacheReusesUnchangedPackages(t *testing.T) {
+	dir := t.TempDir()
+	files := writeCacheModule(t, dir)
+	options := AnalysisOptions{Analyzers: []string{"naming"}, CacheDir: filepath.Join(dir, ".cache")}
+	allNames := []string{"a_other", "a_value", "b_value", "c_value"}
+
+	steps := []struct {
+		name         string
+		change       func()
+		options      AnalysisOptions
+		hits, misses int64
+		names        []string
+	}{
+		{
+			name:    "cold cache",
+			options: options,
+			misses:  4,
+			names:   allNames,
+		},
+		{
+			name:    "unchanged",
+			options: options,
+			hits:    4,
+			names:   allNames,
+		},
+		{
+			name: "file of a package without importers",
+			change: func() {
+				writeFile(t, filepath.Join(dir, "c", "c.go"), "package c\n\nvar c_renamed = 3\n")
+			},
+			options: options,
+			hits:    3,
+			misses:  1,
+			names:   []string{"a_other", "a_value", "b_value", "c_renamed"},
+		},
+		{
+			name: "imported package",
+			change: func() {
+				writeFile(t, filepath.Join(dir, "b", "b.go"), "package b\n\nvar Value = 1\n\nvar b_renamed = 2\n")
+			},
+			options: options,
+			hits:    1,
+			misses:  3,
+			names:   []string{"a_other", "a_value", "b_renamed", "c_renamed"},
+		},
+		{
+			name:    "filter applied after the cache",
+			options: AnalysisOptions{Analyzers: options.Analyzers, CacheDir: options.CacheDir, MinSeverity: "info"},
+			hits:    4,
+			names:   []string{"a_other", "a_value", "b_renamed", "c_renamed"},
+		},
+		{
+			name:    "configuration",
+			options: AnalysisOptions{Analyzers: []string{"naming", "imports"}, CacheDir: options.CacheDir},
+			misses:  4,
+			names:   []string{"a_other", "a_value", "b_renamed", "c_renamed"},
+		},
+		{
+			name:    "previous configuration",
+			options: options,
+			hits:    4,
+			names:   []string{"a_other", "a_value", "b_renamed", "c_renamed"},
+		},
+	}
+
+	for _, step := range steps {
+		if step.change != nil {
+			step.change()
+		}
+		got := analyzeWithCache(t, files, step.options)
+		if got.hits != step.hits || got.misses != step.misses {
+			t.Errorf("%s: got %d hits and %d misses, want %d and %d", step.name, got.hits, got.misses, step.hits, step.misses)
+		}
+		if !reflect.DeepEqual(got.names, step.names) {
+			t.Errorf("%s: reported %v, want %v", step.name, got.names, step.names)
+		}
+	}
+
+	// Cached results match a fresh analysis of the same files
+	uncached := options
+	uncached.CacheDir = ""
+	if got, want := analyzeWithCache(t, files, options), analyzeWithCache(t, files, uncached); !reflect.DeepEqual(got.names, want.names) {
+		t.Errorf("cached %v, uncached %v", got.names, want.names)
+	}
+}
+
+func TestCacheKeysCoverLocalImports(t *testing.T) {
+	dir := t.TempDir()
+	writeModule(t, dir, map[string]string{
+		"m/go.mod":           "module example.com/m\n\ngo 1.19\n\nrequire example.com/lib v0.0.0\n\nreplace example.com/lib => ../lib\n",
+		"m/a/a.go":           "package a\n\nimport \"example.com/lib\"\n\nvar a_value = lib.Value\n",
+		"lib/go.mod":         "module example.com/lib\n\ngo 1.19\n",
+		"lib/lib.go":         "package lib\n\nimport \"example.com/lib/inner\"\n\nvar Value = inner.Value\n",
+		"lib/inner/inner.go": "package inner\n\nvar Value = 1\n",
+		"lib/other/other.go": "package other\n\nvar Value = 1\n",
+	})
+	// Only package a is analyzed; lib and inner are type-checked from source
+	files := []string{filepath.Join(dir, "m", "a", "a.go")}
+	options := AnalysisOptions{Analyzers: []string{"naming"}, CacheDir: filepath.Join(dir, ".cache")}
+
+	steps := []struct {
+		name         string
+		change       string
+		hits, misses int64
+	}{
+		{name: "cold cache", misses: 1},
+		{name: "unrelated local package", change: "lib/other/other.go", hits: 1},
+		{name: "imported local package", change: "lib/lib.go", misses: 1},
+		{name: "package imported by a local package", change: "lib/inner/inner.go", misses: 1},
+	}
+
+	for _, step := range steps {
+		if step.change != "" {
+			filePath := filepath.Join(dir, filepath.FromSlash(step.change))
+			content, err := os.ReadFile(filePath)
+			if err != nil {
+				t.Fatal(err)
+			}
+			writeFile(t, filePath, string(content)+"\nvar Changed = 2\n")
+		}
+		got := analyzeWithCache(t, files, options)
+		if got.hits != step.hits || got.misses != step.misses {
+			t.Errorf("%s: got %d hits and %d misses, want %d and %d", step.name, got.hits, got.misses, step.hits, step.misses)
+		}
+	}
+}
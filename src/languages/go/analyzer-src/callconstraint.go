@@ -0,0 +1,191 @@
+package analyzer
+
+import (
+	"fmt"
+	"go/ast"
+	"go/token"
+	"go/types"
+	"strings"
+)
+
+// calleeSpec is a parsed call-constraint callee: "[package glob#][Type.]Name"
+type calleeSpec struct {
+	pkgGlob  string
+	typeName string
+	funcName string
+}
+
+// parseCalleeSpec splits a callee of the form "payments#ChargeCustomer" or
+// "**/payments#Gateway.ChargeCustomer"; the package glob is optional
+func parseCalleeSpec(callee string) calleeSpec {
+	var spec calleeSpec
+	if idx := strings.LastIndex(callee, "#"); idx >= 0 {
+		spec.pkgGlob = callee[:idx]
+		callee = callee[idx+1:]
+	}
+	if idx := strings.Index(callee, "."); idx >= 0 {
+		spec.typeName = callee[:idx]
+		callee = callee[idx+1:]
+	}
+	spec.funcName = callee
+	return spec
+}
+
+// analyzeCallConstraint reports calls to the rule's callee from files or
+// packages that are not allowed to call it. Calls are resolved with go/types,
+// so method calls through receivers, interfaces and aliased imports are
+// caught; calls inside the callee's own package are always allowed.
+func (r *RuleAnalyzer) analyzeCallConstraint(rule Rule) []Violation {
+	var violations []Violation
+	spec := parseCalleeSpec(rule.Callee)
+	if spec.funcName == "" || (len(rule.AllowFrom) == 0 && len(rule.DenyFrom) == 0) {
+		return violations
+	}
+
+	for filePath, file := range r.parser.files {
+		if r.callerAllowed(rule, filePath) {
+			continue
+		}
+
+		for _, use := range r.calleeUses(filePath, file, spec) {
+			caller := enclosingFunctionName(file, use.Pos())
+			violations = append(violations, r.parser.withRange(r.ruleViolation(rule, filePath,
+				fmt.Sprintf("%s may not be called from %s", rule.Callee, r.parser.relativePath(filePath)),
+				map[string]interface{}{
+					"callee": rule.Callee,
+					"caller": caller,
+				}), use.Pos(), use.End()))
+		}
+	}
+
+	return violations
+}
+
+// callerAllowed applies the rule's allowFrom and denyFrom globs to a calling file
+func (r *RuleAnalyzer) callerAllowed(rule Rule, filePath string) bool {
+	matches := func(patterns []string) bool {
+		for _, pattern := range patterns {
+			if r.parser.matchesFileOrPackage(pattern, filePath) {
+				return true
+			}
+		}
+		return false
+	}
+
+	if len(rule.AllowFrom) > 0 && !matches(rule.AllowFrom) {
+		return false
+	}
+	return !matches(rule.DenyFrom)
+}
+
+// calleeUses returns the identifiers in file that refer to the callee
+func (r *RuleAnalyzer) calleeUses(filePath string, file *ast.File, spec calleeSpec) []*ast.Ident {
+	var uses []*ast.Ident
+
+	typeInfo := r.parser.TypeInfo(filePath)
+	if typeInfo == nil || typeInfo.Pkg == nil {
+		return r.calleeUsesWithoutTypes(filePath, file, spec)
+	}
+
+	// References the type checker could not resolve, such as calls into a
+	// package that failed to load, are matched syntactically
+	unresolved := make(map[*ast.Ident]bool)
+	for _, ident := range r.calleeUsesWithoutTypes(filePath, file, spec) {
+		if typeInfo.Info.Uses[ident] == nil {
+			unresolved[ident] = true
+		}
+	}
+
+	ast.Inspect(file, func(n ast.Node) bool {
+		ident, ok := n.(*ast.Ident)
+		if !ok {
+			return true
+		}
+		if unresolved[ident] {
+			uses = append(uses, ident)
+			return true
+		}
+		fn, ok := typeInfo.Info.Uses[ident].(*types.Func)
+		if !ok || fn.Pkg() == nil || fn.Pkg().Path() == typeInfo.Pkg.Path() {
+			return true
+		}
+		if r.funcMatchesCallee(fn.Origin(), filePath, spec) {
+			uses = append(uses, ident)
+		}
+		return true
+	})
+
+	return uses
+}
+
+// funcMatchesCallee reports whether a resolved function is the rule's callee
+func (r *RuleAnalyzer) funcMatchesCallee(fn *types.Func, filePath string, spec calleeSpec) bool {
+	if fn.Name() != spec.funcName || receiverTypeNameOf(fn) != spec.typeName {
+		return false
+	}
+	return spec.pkgGlob == "" || r.importMatchesTarget(spec.pkgGlob, filePath, fn.Pkg().Path())
+}
+
+// calleeUsesWithoutTypes matches package-qualified function references
+// syntactically when type information is unavailable; methods cannot be
+// resolved this way
+func (r *RuleAnalyzer) calleeUsesWithoutTypes(filePath string, file *ast.File, spec calleeSpec) []*ast.Ident {
+	var uses []*ast.Ident
+	if spec.typeName != "" {
+		return uses
+	}
+
+	qualifiers := make(map[string]bool)
+	for _, importSpec := range file.Imports {
+		importPath := importPathOf(importSpec)
+		if name := localImportName(importSpec); name != "" && (spec.pkgGlob == "" || r.importMatchesTarget(spec.pkgGlob, filePath, importPath)) {
+			qualifiers[name] = true
+		}
+	}
+
+	ast.Inspect(file, func(n ast.Node) bool {
+		selector, ok := n.(*ast.SelectorExpr)
+		if !ok || selector.Sel.Name != spec.funcName {
+			return true
+		}
+		if ident, ok := selector.X.(*ast.Ident); ok && qualifiers[ident.Name] {
+			uses = append(uses, selector.Sel)
+		}
+		return true
+	})
+
+	return uses
+}
+
+// receiverTypeNameOf returns the named receiver type of a method, or "" for functions
+func receiverTypeNameOf(fn *types.Func) string {
+	signature, ok := fn.Type().(*types.Signature)
+	if !ok || signature.Recv() == nil {
+		return ""
+	}
+	recvType := signature.Recv().Type()
+	if pointer, ok := recvType.(*types.Pointer); ok {
+		recvType = pointer.Elem()
+	}
+	if named, ok := recvType.(*types.Named); ok {
+		return named.Obj().Name()
+	}
+	return ""
+}
+
+// enclosingFunctionName returns the name of the function declaration containing pos
+func enclosingFunctionName(file *ast.File, pos token.Pos) string {
+	for _, decl := range file.Decls {
+		funcDecl, ok := decl.(*ast.FuncDecl)
+		if !ok || pos < funcDecl.Pos() || pos >= funcDecl.End() {
+			continue
+		}
+		if funcDecl.Recv != nil && len(funcDecl.Recv.List) > 0 {
+			if recvName, _ := receiverTypeName(funcDecl.Recv.List[0].Type); recvName != "" {
+				return recvName + "." + funcDecl.Name.Name
+			}
+		}
+		return funcDecl.Name.Name
+	}
+	return ""
+}
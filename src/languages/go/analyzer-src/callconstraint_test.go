@@ -0,0 +1,103 @@
+package analyzer
+
+import (
+	"fmt"
+	"io/fs"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"testing"
+)
+
+// testdataFiles returns the absolute paths of the Go files below a testdata
+// directory
+func testdataFiles(t *testing.T, dir string) []string {
+	t.Helper()
+	root, err := filepath.Abs(filepath.Join("testdata", dir))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var files []string
+	err = filepath.WalkDir(root, func(path string, entry fs.DirEntry, err error) error {
+		if err == nil && !entry.IsDir() && filepath.Ext(path) == ".go" {
+			files = append(files, path)
+		}
+		return err
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	sort.Strings(files)
+	return files
+}
+
+func TestCallConstraint(t *testing.T) {
+	files := testdataFiles(t, "callconstraint")
+	root, _ := filepath.Abs(filepath.Join("testdata", "callconstraint"))
+
+	tests := []struct {
+		name          string
+		rule          Rule
+		skipTypeCheck bool
+		want          []string
+	}{
+		{
+			name: "function through an aliased import, exempt inside its package",
+			rule: Rule{Callee: "payments#ChargeCustomer", AllowFrom: []string{"api/**"}},
+			want: []string{"web/cart.go:17 PayNow"},
+		},
+		{
+			name: "method through a receiver field",
+			rule: Rule{Callee: "payments#Gateway.ChargeCustomer", AllowFrom: []string{"api/**"}},
+			want: []string{"web/cart.go:12 Cart.Pay"},
+		},
+		{
+			name: "callee in another package glob",
+			rule: Rule{Callee: "billing#ChargeCustomer", AllowFrom: []string{"api/**"}},
+		},
+		{
+			name: "deny list",
+			rule: Rule{Callee: "example.com/shop/payments#ChargeCustomer", DenyFrom: []string{"api/**"}},
+			want: []string{"api/handler.go:7 Checkout"},
+		},
+		{
+			name:          "function without type information",
+			rule:          Rule{Callee: "payments#ChargeCustomer", AllowFrom: []string{"api/**"}},
+			skipTypeCheck: true,
+			want:          []string{"web/cart.go:17 PayNow"},
+		},
+		{
+			name:          "methods need type information",
+			rule:          Rule{Callee: "payments#Gateway.ChargeCustomer", AllowFrom: []string{"api/**"}},
+			skipTypeCheck: true,
+		},
+	}
+
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			rule := test.rule
+			rule.ID, rule.Kind = "charge", RuleCallConstraint
+			result, err := NewAnalyzer(AnalysisOptions{
+				Analyzers:     []string{"invariants"},
+				Rules:         []Rule{rule},
+				SkipTypeCheck: test.skipTypeCheck,
+			}).Analyze(files)
+			if err != nil {
+				t.Fatalf("analysis failed: %v", err)
+			}
+			if len(result.Errors) != 0 {
+				t.Fatalf("unexpected errors %+v", result.Errors)
+			}
+
+			var calls []string
+			for _, violation := range result.Violations {
+				relative, _ := filepath.Rel(root, violation.File)
+				calls = append(calls, fmt.Sprintf("%s:%d %s", filepath.ToSlash(relative), violation.Line, violation.Details["caller"]))
+			}
+			if !reflect.DeepEqual(calls, test.want) {
+				t.Errorf("got %q, want %q", calls, test.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,268 @@
+package analyzer
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// changeScope holds the changed head lines of each file touched by the
+// configured diff or file pairs
+type changeScope struct {
+	exact    map[string]map[int]bool // Absolute paths
+	relative map[string]map[int]bool // Slash-separated diff paths, matched as suffixes
+	resolved map[string]map[int]bool
+}
+
+// changeScoped reports whether only changed lines are to be reported
+func (a *Analyzer) changeScoped() bool {
+	return a.options.Diff != "" || len(a.options.Changes) > 0
+}
+
+// changeScope collects the changed lines of the configured diff and file
+// pairs. Diff paths are resolved against RootDir when it is set.
+func (a *Analyzer) changeScope() (*changeScope, []Error) {
+	scope := &changeScope{
+		exact:    make(map[string]map[int]bool),
+		relative: make(map[string]map[int]bool),
+		resolved: make(map[string]map[int]bool),
+	}
+	var errors []Error
+
+	if a.options.Diff != "" {
+		files, err := parseUnifiedDiff(a.options.Diff)
+		if err != nil {
+			errors = append(errors, Error{
+				Message: fmt.Sprintf("Diff not applied: %v", err),
+				Type:    "diff",
+			})
+		}
+		for path, lines := range files {
+			if a.options.RootDir != "" {
+				scope.add(scope.exact, absolutePath(filepath.Join(a.options.RootDir, filepath.FromSlash(path))), lines)
+			} else {
+				scope.add(scope.relative, path, lines)
+			}
+		}
+	}
+
+	for _, change := range a.options.Changes {
+		lines, err := a.changedFileLines(change)
+		if err != nil {
+			errors = append(errors, Error{
+				Message: fmt.Sprintf("Change not applied: %v", err),
+				Type:    "diff",
+				File:    change.Head,
+			})
+			continue
+		}
+		scope.add(scope.exact, absolutePath(change.Head), lines)
+	}
+
+	return scope, errors
+}
+
+// add merges changed lines into one of the scope's maps
+func (s *changeScope) add(files map[string]map[int]bool, path string, lines map[int]bool) {
+	if files[path] == nil {
+		files[path] = make(map[int]bool)
+	}
+	for line := range lines {
+		files[path][line] = true
+	}
+}
+
+// linesOf returns the changed lines of an analyzed file, or nil when the
+// file is unchanged
+func (s *changeScope) linesOf(filePath string) map[int]bool {
+	path := absolutePath(filePath)
+	if lines, ok := s.resolved[path]; ok {
+		return lines
+	}
+
+	lines := s.exact[path]
+	slashPath := filepath.ToSlash(path)
+	for relative, relativeLines := range s.relative {
+		if slashPath == relative || strings.HasSuffix(slashPath, "/"+relative) {
+			if lines == nil {
+				lines = make(map[int]bool)
+			}
+			for line := range relativeLines {
+				lines[line] = true
+			}
+		}
+	}
+	s.resolved[path] = lines
+	return lines
+}
+
+// intersects reports whether a violation spans a changed line
+func (s *changeScope) intersects(violation Violation) bool {
+	lines := s.linesOf(violation.File)
+	if len(lines) == 0 {
+		return false
+	}
+	endLine := violation.EndLine
+	if endLine < violation.Line {
+		endLine = violation.Line
+	}
+	for line := violation.Line; line <= endLine; line++ {
+		if lines[line] {
+			return true
+		}
+	}
+	return false
+}
+
+// applyChangeScope keeps only the violations and suppressed findings that
+// intersect a changed line
+func (a *Analyzer) applyChangeScope(result *AnalysisResult) {
+	if !a.changeScoped() {
+		return
+	}
+	scope, errors := a.changeScope()
+	result.Errors = append(result.Errors, errors...)
+
+	violations := []Violation{}
+	for _, violation := range result.Violations {
+		if scope.intersects(violation) {
+			violations = append(violations, violation)
+		}
+	}
+	result.Violations = violations
+
+	suppressed := []SuppressedViolation{}
+	for _, finding := range result.Suppressed {
+		if scope.intersects(finding.Violation) {
+			suppressed = append(suppressed, finding)
+		}
+	}
+	result.Suppressed = suppressed
+}
+
+// changedFileLines diffs the base and head versions of a file pair and
+// returns the head lines that were added, changed or next to a deletion
+func (a *Analyzer) changedFileLines(change FileChange) (map[int]bool, error) {
+	var before []byte
+	if change.Base != "" {
+		var err error
+		if before, err = os.ReadFile(change.Base); err != nil {
+			return nil, err
+		}
+	}
+	after, ok := a.parser.sources[change.Head]
+	if !ok {
+		var err error
+		if after, err = os.ReadFile(change.Head); err != nil {
+			return nil, err
+		}
+	}
+
+	lines := make(map[int]bool)
+	newLine := 1
+	for _, op := range diffLines(splitDiffLines(before), splitDiffLines(after)) {
+		switch op.kind {
+		case '+':
+			lines[newLine] = true
+			newLine++
+		case '-':
+			lines[newLine] = true
+		default:
+			newLine++
+		}
+	}
+	return lines, nil
+}
+
+// parseUnifiedDiff returns the changed head lines of each file in a unified
+// diff, keyed by its slash-separated "+++" path with any "b/" prefix
+// removed. A deletion marks the head line that follows it, so removed
+// statements are attributed to the code around them.
+func parseUnifiedDiff(diff string) (map[string]map[int]bool, error) {
+	files := make(map[string]map[int]bool)
+	var current map[int]bool
+	newLine, oldRemaining, newRemaining := 0, 0, 0
+
+	for index, line := range strings.Split(diff, "\n") {
+		inHunk := oldRemaining > 0 || newRemaining > 0
+		switch {
+		case inHunk && strings.HasPrefix(line, "+"):
+			if current != nil {
+				current[newLine] = true
+			}
+			newLine++
+			newRemaining--
+		case inHunk && strings.HasPrefix(line, "-"):
+			if current != nil {
+				current[newLine] = true
+			}
+			oldRemaining--
+		case inHunk && (strings.HasPrefix(line, " ") || line == ""):
+			newLine++
+			oldRemaining--
+			newRemaining--
+		case strings.HasPrefix(line, `\`):
+			// "\ No newline at end of file"
+		case strings.HasPrefix(line, "+++ "):
+			path := strings.TrimPrefix(line, "+++ ")
+			if tab := strings.IndexByte(path, '\t'); tab >= 0 {
+				path = path[:tab]
+			}
+			if path == "/dev/null" {
+				current = nil
+				continue
+			}
+			path = strings.TrimPrefix(path, "b/")
+			if files[path] == nil {
+				files[path] = make(map[int]bool)
+			}
+			current = files[path]
+		case strings.HasPrefix(line, "@@ "):
+			oldCount, newStart, newCount, ok := parseHunkHeader(line)
+			if !ok {
+				return files, fmt.Errorf("line %d: malformed hunk header %q", index+1, line)
+			}
+			newLine, oldRemaining, newRemaining = newStart, oldCount, newCount
+			if newCount == 0 {
+				newLine++ // An empty side names the line before it
+			}
+		}
+	}
+
+	return files, nil
+}
+
+// parseHunkHeader parses the counts and head start of "@@ -a,b +c,d @@";
+// omitted counts are 1
+func parseHunkHeader(line string) (oldCount, newStart, newCount int, ok bool) {
+	fields := strings.Fields(line)
+	if len(fields) < 4 || fields[3] != "@@" {
+		return 0, 0, 0, false
+	}
+	if _, oldCount, ok = parseHunkRange(fields[1], "-"); !ok {
+		return 0, 0, 0, false
+	}
+	newStart, newCount, ok = parseHunkRange(fields[2], "+")
+	return oldCount, newStart, newCount, ok
+}
+
+// parseHunkRange parses one side of a hunk header
+func parseHunkRange(field, prefix string) (start, count int, ok bool) {
+	if !strings.HasPrefix(field, prefix) {
+		return 0, 0, false
+	}
+	startText, countText, hasCount := strings.Cut(field[len(prefix):], ",")
+	start, err := strconv.Atoi(startText)
+	if err != nil {
+		return 0, 0, false
+	}
+	count = 1
+	if hasCount {
+		if count, err = strconv.Atoi(countText); err != nil {
+			return 0, 0, false
+		}
+	}
+	return start, count, true
+}
@@ -0,0 +1,93 @@
+package analyzer
+
+import (
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+)
+
+// changedLines lists the changed lines of each file in ascending order
+func changedLines(files map[string]map[int]bool) map[string][]int {
+	lines := make(map[string][]int)
+	for path, changed := range files {
+		lines[path] = []int{}
+		for line := range changed {
+			lines[path] = append(lines[path], line)
+		}
+		sort.Ints(lines[path])
+	}
+	return lines
+}
+
+func TestParseUnifiedDiff(t *testing.T) {
+	tests := []struct {
+		name string
+		diff []string
+		want map[string][]int
+	}{
+		{
+			name: "changed line",
+			diff: []string{"--- a/f.go", "+++ b/f.go", "@@ -1,3 +1,3 @@", " a", "-b", "+B", " c"},
+			want: map[string][]int{"f.go": {2}},
+		},
+		{
+			name: "deletion between context lines",
+			diff: []string{"--- a/f.go", "+++ b/f.go", "@@ -1,3 +1,2 @@", " a", "-b", " c"},
+			want: map[string][]int{"f.go": {2}},
+		},
+		{
+			name: "pure deletion hunk",
+			diff: []string{"--- a/f.go", "+++ b/f.go", "@@ -3,2 +2,0 @@", "-x", "-y"},
+			want: map[string][]int{"f.go": {3}},
+		},
+		{
+			name: "no newline at end of file",
+			diff: []string{"--- a/f.go", "+++ b/f.go", "@@ -1,2 +1,2 @@", " a", "-b", `\ No newline at end of file`, "+b",
+				"--- a/g.go", "+++ b/g.go", "@@ -1 +1 @@", "-x", "+y", `\ No newline at end of file`},
+			want: map[string][]int{"f.go": {2}, "g.go": {1}},
+		},
+		{
+			name: "file markers inside a hunk",
+			diff: []string{"--- a/f.go", "+++ b/f.go", "@@ -1,2 +1,2 @@", " a", "--- old", "+++ new"},
+			want: map[string][]int{"f.go": {2}},
+		},
+		{
+			name: "blank context line without a space",
+			diff: []string{"--- a/f.go", "+++ b/f.go", "@@ -1,3 +1,3 @@", " a", "", "-c", "+C"},
+			want: map[string][]int{"f.go": {3}},
+		},
+		{
+			name: "new and deleted files",
+			diff: []string{"--- /dev/null", "+++ b/new.go", "@@ -0,0 +1,2 @@", "+a", "+b",
+				"--- a/old.go", "+++ /dev/null", "@@ -1,2 +0,0 @@", "-a", "-b"},
+			want: map[string][]int{"new.go": {1, 2}},
+		},
+		{
+			name: "timestamped paths and several hunks",
+			diff: []string{"--- dir/f.go\t2026-01-01 00:00:00", "+++ dir/f.go\t2026-01-02 00:00:00",
+				"@@ -1,2 +1,3 @@", " a", "+b", " c", "@@ -10,1 +11,1 @@", "-j", "+J"},
+			want: map[string][]int{"dir/f.go": {2, 11}},
+		},
+	}
+
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			files, err := parseUnifiedDiff(strings.Join(test.diff, "\n") + "\n")
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := changedLines(files); !reflect.DeepEqual(got, test.want) {
+				t.Errorf("got %v, want %v", got, test.want)
+			}
+		})
+	}
+}
+
+func TestParseUnifiedDiffMalformedHunk(t *testing.T) {
+	_, err := parseUnifiedDiff("--- a/f.go\n+++ b/f.go\n@@ -1,x +1 @@\n-a\n")
+	if err == nil || !strings.Contains(err.Error(), "line 3: malformed hunk header") {
+		t.Errorf("got error %v, want a malformed hunk header on line 3", err)
+	}
+}
@@ -0,0 +1,203 @@
+package analyzer
+
+import (
+	"fmt"
+	"go/ast"
+	"go/token"
+	"go/types"
+	"strings"
+)
+
+// cancelingConstructors are the context functions returning a cancel function
+var cancelingConstructors = map[string]bool{
+	"WithCancel":        true,
+	"WithCancelCause":   true,
+	"WithDeadline":      true,
+	"WithDeadlineCause": true,
+	"WithTimeout":       true,
+	"WithTimeoutCause":  true,
+}
+
+// analyzeLostCancel reports cancel functions from context.WithCancel,
+// WithTimeout and WithDeadline that are discarded or never used, and offers
+// to defer the call right after the assignment
+func (a *Analyzer) analyzeLostCancel() []Violation {
+	var violations []Violation
+
+	for filePath, file := range a.parser.files {
+		typeInfo := a.parser.TypeInfo(filePath)
+		contextNames := importNamesFor(file, "context")
+		if len(contextNames) == 0 {
+			continue
+		}
+
+		ast.Inspect(file, func(n ast.Node) bool {
+			var body *ast.BlockStmt
+			switch node := n.(type) {
+			case *ast.FuncDecl:
+				body = node.Body
+			case *ast.FuncLit:
+				body = node.Body
+			}
+			if body == nil {
+				return true
+			}
+
+			for _, stmt := range statementsOf(body) {
+				assign, ok := stmt.(*ast.AssignStmt)
+				if !ok || len(assign.Lhs) != 2 || len(assign.Rhs) != 1 {
+					continue
+				}
+				call, ok := assign.Rhs[0].(*ast.CallExpr)
+				if !ok {
+					continue
+				}
+				constructor := cancelingConstructor(call, typeInfo, contextNames)
+				cancel, ok := assign.Lhs[1].(*ast.Ident)
+				if constructor == "" || !ok {
+					continue
+				}
+				if cancel.Name != "_" && cancelReferenced(body, cancel, typeInfo) {
+					continue
+				}
+				violations = append(violations, a.lostCancelViolation(filePath, n, assign, cancel, constructor))
+			}
+			return true
+		})
+	}
+
+	return violations
+}
+
+// lostCancelViolation reports one unused cancel function in fn with its fix.
+// A discarded cancel is only named by a := assignment, which declares it.
+func (a *Analyzer) lostCancelViolation(filePath string, fn ast.Node, assign *ast.AssignStmt, cancel *ast.Ident, constructor string) Violation {
+	message := fmt.Sprintf("The cancel function returned by context.%s is never called; the context leaks until its parent is canceled", constructor)
+	if cancel.Name == "_" {
+		message = fmt.Sprintf("The cancel function returned by context.%s is discarded; the context leaks until its parent is canceled", constructor)
+	}
+
+	violation := Violation{
+		File:     filePath,
+		Severity: "warning",
+		Message:  message,
+		Details: map[string]interface{}{
+			"constructor": "context." + constructor,
+			"cancel":      cancel.Name,
+		},
+		Suggestion: "Call the cancel function when the operation completes, usually with defer",
+		Analyzer:   "goroutines",
+		Category:   "context-leak",
+	}
+
+	name := cancel.Name
+	var edits []TextEdit
+	if name == "_" {
+		name = "cancel"
+		if assign.Tok != token.DEFINE || identifierUsed(fn, name) {
+			name = ""
+		} else {
+			edits = append(edits, a.parser.textEdit(cancel.Pos(), cancel.End(), name))
+		}
+	}
+	if name != "" {
+		indent := a.parser.lineIndent(assign.Pos())
+		edits = append(edits, a.parser.insertEdit(assign.End(), "\n"+indent+"defer "+name+"()"))
+		violation.Fixes = []Fix{{
+			Description: fmt.Sprintf("Defer %s()", name),
+			Edits:       edits,
+		}}
+	}
+
+	return a.parser.withRange(violation, assign.Pos(), assign.End())
+}
+
+// cancelingConstructor returns the name of the context constructor a call
+// invokes, or "" when it is not one returning a cancel function
+func cancelingConstructor(call *ast.CallExpr, typeInfo *PackageTypes, contextNames map[string]bool) string {
+	selector, ok := call.Fun.(*ast.SelectorExpr)
+	if !ok || !cancelingConstructors[selector.Sel.Name] {
+		return ""
+	}
+	if typeInfo != nil {
+		if fn, ok := typeInfo.Info.Uses[selector.Sel].(*types.Func); ok {
+			if fn.Pkg() != nil && fn.Pkg().Path() == "context" {
+				return fn.Name()
+			}
+			return ""
+		}
+	}
+	if ident, ok := selector.X.(*ast.Ident); ok && contextNames[ident.Name] {
+		return selector.Sel.Name
+	}
+	return ""
+}
+
+// cancelReferenced reports whether a cancel variable is used anywhere in the
+// function body besides its assignment
+func cancelReferenced(body *ast.BlockStmt, cancel *ast.Ident, typeInfo *PackageTypes) bool {
+	var obj types.Object
+	if typeInfo != nil {
+		obj = typeInfo.Info.ObjectOf(cancel)
+	}
+
+	referenced := false
+	ast.Inspect(body, func(n ast.Node) bool {
+		ident, ok := n.(*ast.Ident)
+		if !ok || ident == cancel || referenced {
+			return !referenced
+		}
+		if obj != nil {
+			referenced = typeInfo.Info.Uses[ident] == obj
+		} else {
+			referenced = ident.Name == cancel.Name
+		}
+		return !referenced
+	})
+	return referenced
+}
+
+// identifierUsed reports whether a name appears anywhere in a function,
+// including its receiver, parameters and results
+func identifierUsed(fn ast.Node, name string) bool {
+	used := false
+	ast.Inspect(fn, func(n ast.Node) bool {
+		if ident, ok := n.(*ast.Ident); ok && ident.Name == name {
+			used = true
+		}
+		return !used
+	})
+	return used
+}
+
+// statementsOf returns the statements of a block and of the blocks nested in
+// it, stopping at function literals, which are visited on their own
+func statementsOf(body *ast.BlockStmt) []ast.Stmt {
+	var stmts []ast.Stmt
+	ast.Inspect(body, func(n ast.Node) bool {
+		switch node := n.(type) {
+		case *ast.FuncLit:
+			return false
+		case *ast.BlockStmt:
+			stmts = append(stmts, node.List...)
+		case *ast.CaseClause:
+			stmts = append(stmts, node.Body...)
+		case *ast.CommClause:
+			stmts = append(stmts, node.Body...)
+		}
+		return true
+	})
+	return stmts
+}
+
+// lineIndent returns the leading whitespace of the line containing pos
+func (p *Parser) lineIndent(pos token.Pos) string {
+	position := p.fileSet.Position(pos)
+	src := p.sources[position.Filename]
+	start := position.Offset - (position.Column - 1)
+	if src == nil || start < 0 || position.Offset > len(src) {
+		return ""
+	}
+	line := string(src[start:position.Offset])
+	return line[:len(line)-len(strings.TrimLeft(line, " \t"))]
+}
@@ -0,0 +1,266 @@
+package analyzer
+
+import (
+	"fmt"
+	"go/ast"
+	"sort"
+	"strings"
+)
+
+// DependencyAnalyzer analyzes the package import graph for cycles and
+// violations of the declared layer ordering
+type DependencyAnalyzer struct {
+	parser   *Parser
+	layers   []Layer
+	packages []Package
+	graph    map[string][]string
+}
+
+// NewDependencyAnalyzer creates a new dependency analyzer
+func NewDependencyAnalyzer(parser *Parser, layers []Layer) *DependencyAnalyzer {
+	packages := parser.Packages()
+
+	known := make(map[string]bool)
+	for _, pkg := range packages {
+		known[pkg.Path] = true
+	}
+
+	// Only edges between parsed packages can take part in a cycle
+	graph := make(map[string][]string)
+	for _, pkg := range packages {
+		for _, importPath := range pkg.Imports {
+			if known[importPath] {
+				graph[pkg.Path] = append(graph[pkg.Path], importPath)
+			}
+		}
+	}
+
+	return &DependencyAnalyzer{
+		parser:   parser,
+		layers:   layers,
+		packages: packages,
+		graph:    graph,
+	}
+}
+
+// Analyze performs import cycle and layering analysis
+func (d *DependencyAnalyzer) Analyze() []Violation {
+	var violations []Violation
+
+	violations = append(violations, d.analyzeCycles()...)
+	violations = append(violations, d.analyzeLayers()...)
+
+	return violations
+}
+
+// analyzeCycles reports one violation per strongly connected component of the import graph
+func (d *DependencyAnalyzer) analyzeCycles() []Violation {
+	var violations []Violation
+
+	for _, component := range d.stronglyConnectedComponents() {
+		cycle := d.shortestCycle(component)
+		if len(cycle) == 0 {
+			continue
+		}
+
+		importSpec, filePath := d.findImport(cycle[0], cycle[1])
+		if importSpec == nil {
+			continue
+		}
+		violations = append(violations, d.parser.withRange(Violation{
+			File:     filePath,
+			Severity: "critical",
+			Message:  "Import cycle detected: " + strings.Join(cycle, " -> "),
+			Details: map[string]interface{}{
+				"cycle":    cycle,
+				"packages": component,
+			},
+			Suggestion: "Break the cycle by moving shared types into a lower-level package or depending on an interface",
+			Analyzer:   "dependencies",
+			Category:   "import-cycle",
+		}, importSpec.Pos(), importSpec.End()))
+	}
+
+	return violations
+}
+
+// analyzeLayers reports imports from a lower layer into a higher one
+func (d *DependencyAnalyzer) analyzeLayers() []Violation {
+	var violations []Violation
+	if len(d.layers) == 0 {
+		return violations
+	}
+
+	for _, pkg := range d.packages {
+		fromLayer := d.layerOf(pkg.Path)
+		if fromLayer < 0 {
+			continue
+		}
+		for _, filePath := range pkg.Files {
+			if strings.HasSuffix(filePath, "_test.go") {
+				continue
+			}
+			for _, importSpec := range d.parser.files[filePath].Imports {
+				importPath := importPathOf(importSpec)
+				toLayer := d.layerOf(importPath)
+				if toLayer < 0 || toLayer >= fromLayer {
+					continue
+				}
+				violations = append(violations, d.parser.withRange(Violation{
+					File:     filePath,
+					Severity: "warning",
+					Message: fmt.Sprintf("Package %s in layer %q imports %s from higher layer %q",
+						pkg.Path, d.layers[fromLayer].Name, importPath, d.layers[toLayer].Name),
+					Details: map[string]interface{}{
+						"package":   pkg.Path,
+						"import":    importPath,
+						"fromLayer": d.layers[fromLayer].Name,
+						"toLayer":   d.layers[toLayer].Name,
+					},
+					Suggestion: "Lower layers must not depend on higher layers; invert the dependency with an interface",
+					Analyzer:   "dependencies",
+					Category:   "layer-violation",
+				}, importSpec.Pos(), importSpec.End()))
+			}
+		}
+	}
+
+	return violations
+}
+
+// layerOf returns the index of the first layer matching an import path, or -1.
+// Layers are declared from highest (index 0) to lowest.
+func (d *DependencyAnalyzer) layerOf(importPath string) int {
+	for index, layer := range d.layers {
+		if matchAnyGlob(layer.Packages, importPath) {
+			return index
+		}
+	}
+	return -1
+}
+
+// stronglyConnectedComponents returns the cyclic components of the import
+// graph (Tarjan's algorithm), each sorted, in a stable order
+func (d *DependencyAnalyzer) stronglyConnectedComponents() [][]string {
+	index := 0
+	indices := make(map[string]int)
+	lowlinks := make(map[string]int)
+	onStack := make(map[string]bool)
+	var stack []string
+	var components [][]string
+
+	var connect func(node string)
+	connect = func(node string) {
+		indices[node] = index
+		lowlinks[node] = index
+		index++
+		stack = append(stack, node)
+		onStack[node] = true
+
+		for _, next := range d.graph[node] {
+			if _, visited := indices[next]; !visited {
+				connect(next)
+				if lowlinks[next] < lowlinks[node] {
+					lowlinks[node] = lowlinks[next]
+				}
+			} else if onStack[next] && indices[next] < lowlinks[node] {
+				lowlinks[node] = indices[next]
+			}
+		}
+
+		if lowlinks[node] != indices[node] {
+			return
+		}
+		var component []string
+		for {
+			top := stack[len(stack)-1]
+			stack = stack[:len(stack)-1]
+			onStack[top] = false
+			component = append(component, top)
+			if top == node {
+				break
+			}
+		}
+		if len(component) > 1 || d.importsItself(node) {
+			sort.Strings(component)
+			components = append(components, component)
+		}
+	}
+
+	for _, pkg := range d.packages {
+		if _, visited := indices[pkg.Path]; !visited {
+			connect(pkg.Path)
+		}
+	}
+
+	sort.Slice(components, func(i, j int) bool {
+		return components[i][0] < components[j][0]
+	})
+	return components
+}
+
+// shortestCycle finds the shortest cycle through the first package of a
+// component, returned with the start repeated at the end
+func (d *DependencyAnalyzer) shortestCycle(component []string) []string {
+	start := component[0]
+	inComponent := make(map[string]bool)
+	for _, node := range component {
+		inComponent[node] = true
+	}
+
+	previous := map[string]string{}
+	queue := []string{start}
+	visited := map[string]bool{}
+	for len(queue) > 0 {
+		node := queue[0]
+		queue = queue[1:]
+		for _, next := range d.graph[node] {
+			if !inComponent[next] {
+				continue
+			}
+			if next == start {
+				cycle := []string{start}
+				for current := node; current != start; current = previous[current] {
+					cycle = append([]string{current}, cycle...)
+				}
+				return append([]string{start}, cycle...)
+			}
+			if !visited[next] {
+				visited[next] = true
+				previous[next] = node
+				queue = append(queue, next)
+			}
+		}
+	}
+	return nil
+}
+
+// importsItself reports whether a package imports its own path
+func (d *DependencyAnalyzer) importsItself(pkgPath string) bool {
+	for _, next := range d.graph[pkgPath] {
+		if next == pkgPath {
+			return true
+		}
+	}
+	return false
+}
+
+// findImport locates the import spec in package from that imports package to
+func (d *DependencyAnalyzer) findImport(from, to string) (*ast.ImportSpec, string) {
+	for _, pkg := range d.packages {
+		if pkg.Path != from {
+			continue
+		}
+		for _, filePath := range pkg.Files {
+			if strings.HasSuffix(filePath, "_test.go") {
+				continue
+			}
+			for _, importSpec := range d.parser.files[filePath].Imports {
+				if importPathOf(importSpec) == to {
+					return importSpec, filePath
+				}
+			}
+		}
+	}
+	return nil, ""
+}
@@ -0,0 +1,167 @@
+package analyzer
+
+import (
+	"fmt"
+	"strings"
+)
+
+// diffContextLines is the number of unchanged lines around each hunk
+const diffContextLines = 3
+
+// diffOp is one line of an edit script: ' ' kept, '-' deleted or '+' inserted
+type diffOp struct {
+	kind byte
+	text string
+}
+
+// unifiedDiff renders the line differences between two versions of a file
+// in unified format, or "" when they are equal
+func unifiedDiff(path string, before, after []byte) string {
+	ops := diffLines(splitDiffLines(before), splitDiffLines(after))
+
+	// Line numbers in the old and new file before each operation
+	oldLines := make([]int, len(ops)+1)
+	newLines := make([]int, len(ops)+1)
+	for index, op := range ops {
+		oldLines[index+1], newLines[index+1] = oldLines[index], newLines[index]
+		if op.kind != '+' {
+			oldLines[index+1]++
+		}
+		if op.kind != '-' {
+			newLines[index+1]++
+		}
+	}
+
+	var builder strings.Builder
+	for index := 0; index < len(ops); {
+		if ops[index].kind == ' ' {
+			index++
+			continue
+		}
+
+		// Extend the hunk over changes separated by little unchanged context
+		start := index - diffContextLines
+		if start < 0 {
+			start = 0
+		}
+		end := index
+		for {
+			for end < len(ops) && ops[end].kind != ' ' {
+				end++
+			}
+			next := end
+			for next < len(ops) && ops[next].kind == ' ' {
+				next++
+			}
+			if next == len(ops) || next-end > 2*diffContextLines {
+				break
+			}
+			end = next
+		}
+		stop := end + diffContextLines
+		if stop > len(ops) {
+			stop = len(ops)
+		}
+
+		if builder.Len() == 0 {
+			fmt.Fprintf(&builder, "--- a/%s\n+++ b/%s\n", path, path)
+		}
+		fmt.Fprintf(&builder, "@@ -%s +%s @@\n",
+			hunkRange(oldLines[start], oldLines[stop]-oldLines[start]),
+			hunkRange(newLines[start], newLines[stop]-newLines[start]))
+		for _, op := range ops[start:stop] {
+			builder.WriteByte(op.kind)
+			builder.WriteString(op.text)
+			builder.WriteByte('\n')
+		}
+		index = stop
+	}
+
+	return builder.String()
+}
+
+// hunkRange formats the start,count of a hunk side; an empty side names the
+// line before it
+func hunkRange(before, count int) string {
+	if count == 0 {
+		return fmt.Sprintf("%d,0", before)
+	}
+	return fmt.Sprintf("%d,%d", before+1, count)
+}
+
+// splitDiffLines splits content into lines without their terminators
+func splitDiffLines(content []byte) []string {
+	text := strings.TrimSuffix(string(content), "\n")
+	if text == "" {
+		return nil
+	}
+	return strings.Split(text, "\n")
+}
+
+// diffLines computes a shortest edit script between two line slices with
+// Myers' algorithm
+func diffLines(a, b []string) []diffOp {
+	n, m := len(a), len(b)
+	maxEdits := n + m
+	offset := maxEdits + 1
+	v := make([]int, 2*maxEdits+3)
+	var trace [][]int
+
+search:
+	for d := 0; d <= maxEdits; d++ {
+		trace = append(trace, append([]int(nil), v...))
+		for k := -d; k <= d; k += 2 {
+			var x int
+			if k == -d || (k != d && v[offset+k-1] < v[offset+k+1]) {
+				x = v[offset+k+1]
+			} else {
+				x = v[offset+k-1] + 1
+			}
+			y := x - k
+			for x < n && y < m && a[x] == b[y] {
+				x++
+				y++
+			}
+			v[offset+k] = x
+			if x >= n && y >= m {
+				break search
+			}
+		}
+	}
+
+	// Walk the trace back from the end to recover the operations
+	var ops []diffOp
+	x, y := n, m
+	for d := len(trace) - 1; d >= 0; d-- {
+		v := trace[d]
+		k := x - y
+		var prevK int
+		if k == -d || (k != d && v[offset+k-1] < v[offset+k+1]) {
+			prevK = k + 1
+		} else {
+			prevK = k - 1
+		}
+		prevX := v[offset+prevK]
+		prevY := prevX - prevK
+
+		for x > prevX && y > prevY {
+			ops = append(ops, diffOp{kind: ' ', text: a[x-1]})
+			x--
+			y--
+		}
+		if d > 0 {
+			if x == prevX {
+				ops = append(ops, diffOp{kind: '+', text: b[y-1]})
+				y--
+			} else {
+				ops = append(ops, diffOp{kind: '-', text: a[x-1]})
+				x--
+			}
+		}
+	}
+
+	for i, j := 0, len(ops)-1; i < j; i, j = i+1, j-1 {
+		ops[i], ops[j] = ops[j], ops[i]
+	}
+	return ops
+}
@@ -0,0 +1,87 @@
+package analyzer
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+// numberedLines returns "1\n2\n...n\n"
+func numberedLines(n int) string {
+	var builder strings.Builder
+	for line := 1; line <= n; line++ {
+		fmt.Fprintf(&builder, "%d\n", line)
+	}
+	return builder.String()
+}
+
+func TestUnifiedDiff(t *testing.T) {
+	tests := []struct {
+		name          string
+		before, after string
+		want          string
+	}{
+		{
+			name:   "equal",
+			before: "a\nb\n",
+			after:  "a\nb\n",
+			want:   "",
+		},
+		{
+			name:   "changed line",
+			before: "a\nb\nc\n",
+			after:  "a\nB\nc\n",
+			want:   "@@ -1,3 +1,3 @@\n a\n-b\n+B\n c\n",
+		},
+		{
+			name:   "new file",
+			before: "",
+			after:  "a\nb\n",
+			want:   "@@ -0,0 +1,2 @@\n+a\n+b\n",
+		},
+		{
+			name:   "deleted content",
+			before: "a\nb\n",
+			after:  "",
+			want:   "@@ -1,2 +0,0 @@\n-a\n-b\n",
+		},
+		{
+			name:   "context is limited to three lines",
+			before: numberedLines(10),
+			after:  strings.Replace(numberedLines(10), "\n5\n", "\nfive\n", 1),
+			want:   "@@ -2,7 +2,7 @@\n 2\n 3\n 4\n-5\n+five\n 6\n 7\n 8\n",
+		},
+		{
+			name:   "nearby changes share a hunk",
+			before: numberedLines(12),
+			after:  strings.NewReplacer("\n2\n", "\ntwo\n", "\n8\n", "\neight\n").Replace(numberedLines(12)),
+			want:   "@@ -1,11 +1,11 @@\n 1\n-2\n+two\n 3\n 4\n 5\n 6\n 7\n-8\n+eight\n 9\n 10\n 11\n",
+		},
+		{
+			name:   "distant changes get separate hunks",
+			before: numberedLines(20),
+			after:  strings.NewReplacer("\n2\n", "\ntwo\n", "\n18\n", "\neighteen\n").Replace(numberedLines(20)),
+			want: "@@ -1,5 +1,5 @@\n 1\n-2\n+two\n 3\n 4\n 5\n" +
+				"@@ -15,6 +15,6 @@\n 15\n 16\n 17\n-18\n+eighteen\n 19\n 20\n",
+		},
+		{
+			name:   "inserted lines",
+			before: "a\nc\n",
+			after:  "a\nb\nc\n",
+			want:   "@@ -1,2 +1,3 @@\n a\n+b\n c\n",
+		},
+	}
+
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			want := test.want
+			if want != "" {
+				want = "--- a/f.go\n+++ b/f.go\n" + want
+			}
+			if got := unifiedDiff("f.go", []byte(test.before), []byte(test.after)); got != want {
+				t.Errorf("got\n%s\nwant\n%s", got, want)
+			}
+		})
+	}
+}
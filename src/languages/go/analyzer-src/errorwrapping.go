@@ -0,0 +1,151 @@
+package analyzer
+
+import (
+	"fmt"
+	"go/ast"
+	"go/token"
+	"go/types"
+	"strings"
+)
+
+// formatVerb is one verb of a format string: its byte offset in the string
+// literal source and the index of the argument it formats
+type formatVerb struct {
+	verb   byte
+	offset int
+	arg    int
+}
+
+// analyzeErrorWrapping reports fmt.Errorf calls that format an error with %v
+// or %s, losing it for errors.Is and errors.As, and offers to wrap it with %w
+func (a *Analyzer) analyzeErrorWrapping() []Violation {
+	var violations []Violation
+
+	for filePath, file := range a.parser.files {
+		typeInfo := a.parser.TypeInfo(filePath)
+		fmtNames := importNamesFor(file, "fmt")
+
+		ast.Inspect(file, func(n ast.Node) bool {
+			call, ok := n.(*ast.CallExpr)
+			if !ok || len(call.Args) < 2 || !isErrorfCall(call, typeInfo, fmtNames) {
+				return true
+			}
+			format, ok := call.Args[0].(*ast.BasicLit)
+			if !ok || format.Kind != token.STRING {
+				return true
+			}
+			verbs, ok := parseFormatVerbs(format.Value)
+			if !ok {
+				return true
+			}
+
+			var wrap *formatVerb
+			for index := range verbs {
+				verb := verbs[index]
+				if verb.verb == 'w' {
+					return true // Already wraps an error
+				}
+				argIndex := verb.arg + 1
+				if wrap == nil && (verb.verb == 'v' || verb.verb == 's') && argIndex < len(call.Args) &&
+					isErrorExpr(call.Args[argIndex], typeInfo) {
+					wrap = &verbs[index]
+				}
+			}
+			if wrap == nil {
+				return true
+			}
+
+			arg := types.ExprString(call.Args[wrap.arg+1])
+			verbPos := format.Pos() + token.Pos(wrap.offset)
+			violations = append(violations, a.parser.withRange(Violation{
+				File:     filePath,
+				Severity: "suggestion",
+				Message:  fmt.Sprintf("fmt.Errorf formats error %s with %%%c instead of wrapping it with %%w", arg, wrap.verb),
+				Details: map[string]interface{}{
+					"error": arg,
+					"verb":  "%" + string(wrap.verb),
+				},
+				Fixes: []Fix{{
+					Description: fmt.Sprintf("Wrap %s with %%w", arg),
+					Edits:       []TextEdit{a.parser.textEdit(verbPos, verbPos+1, "w")},
+				}},
+				Suggestion: "Use %w so callers can inspect the cause with errors.Is and errors.As",
+				Analyzer:   "errors",
+				Category:   "error-wrapping",
+			}, call.Pos(), call.End()))
+			return true
+		})
+	}
+
+	return violations
+}
+
+// isErrorfCall reports whether a call is fmt.Errorf
+func isErrorfCall(call *ast.CallExpr, typeInfo *PackageTypes, fmtNames map[string]bool) bool {
+	selector, ok := call.Fun.(*ast.SelectorExpr)
+	if !ok || selector.Sel.Name != "Errorf" {
+		return false
+	}
+	if typeInfo != nil {
+		if fn, ok := typeInfo.Info.Uses[selector.Sel].(*types.Func); ok {
+			return fn.Pkg() != nil && fn.Pkg().Path() == "fmt"
+		}
+	}
+	ident, ok := selector.X.(*ast.Ident)
+	return ok && fmtNames[ident.Name]
+}
+
+// isErrorExpr reports whether an expression is an error, by type when known
+// and otherwise by the err/Err naming convention
+func isErrorExpr(expr ast.Expr, typeInfo *PackageTypes) bool {
+	if typeInfo != nil {
+		if exprType := typeInfo.Info.TypeOf(expr); exprType != nil {
+			errorType := types.Universe.Lookup("error").Type()
+			return types.Identical(exprType, errorType)
+		}
+	}
+	ident, ok := expr.(*ast.Ident)
+	return ok && (ident.Name == "err" || strings.HasSuffix(ident.Name, "Err") || strings.HasSuffix(ident.Name, "Error"))
+}
+
+// parseFormatVerbs lists the verbs of a quoted format string literal; it
+// gives up on explicit argument indexes and * widths
+func parseFormatVerbs(literal string) ([]formatVerb, bool) {
+	var verbs []formatVerb
+	arg := 0
+	for i := 0; i < len(literal); i++ {
+		if literal[i] != '%' {
+			continue
+		}
+		i++
+		// Flags, width and precision
+		for i < len(literal) && strings.IndexByte("+-# 0123456789.", literal[i]) >= 0 {
+			i++
+		}
+		if i >= len(literal) {
+			break
+		}
+		switch literal[i] {
+		case '%':
+			continue
+		case '"', '`':
+			return verbs, true // A trailing % ends the literal
+		case '[', '*':
+			return nil, false
+		}
+		verbs = append(verbs, formatVerb{verb: literal[i], offset: i, arg: arg})
+		arg++
+	}
+	return verbs, true
+}
+
+// importNamesFor returns the names a file uses for an import path
+func importNamesFor(file *ast.File, importPath string) map[string]bool {
+	names := make(map[string]bool)
+	for _, importSpec := range file.Imports {
+		if importPathOf(importSpec) == importPath {
+			names[localImportName(importSpec)] = true
+		}
+	}
+	return names
+}
@@ -0,0 +1,99 @@
+package analyzer
+
+import (
+	"fmt"
+	"go/ast"
+	"go/constant"
+	"go/token"
+	"go/types"
+	"strings"
+)
+
+// runExhaustiveSwitches reports switches over an enum type (an IsEnum fact)
+// without a default clause that leave some of its constants unhandled.
+// Without type information there is nothing to compare and no report.
+func runExhaustiveSwitches(pass *Pass) []Violation {
+	var violations []Violation
+
+	for filePath, file := range pass.Parser.files {
+		typeInfo := pass.Parser.TypeInfo(filePath)
+		if typeInfo == nil || typeInfo.Pkg == nil {
+			continue
+		}
+		info := typeInfo.Info
+
+		ast.Inspect(file, func(n ast.Node) bool {
+			switchStmt, ok := n.(*ast.SwitchStmt)
+			if !ok || switchStmt.Tag == nil {
+				return true
+			}
+			named, ok := info.TypeOf(switchStmt.Tag).(*types.Named)
+			if !ok {
+				return true
+			}
+			var enum IsEnum
+			if !pass.ImportObjectFact(named.Obj(), &enum) {
+				return true
+			}
+
+			missing := missingEnumCases(switchStmt, named, enum, info)
+			if len(missing) > 0 {
+				violations = append(violations, pass.Parser.withRange(Violation{
+					File:     filePath,
+					Severity: "warning",
+					Message:  fmt.Sprintf("Switch on %s is missing cases %s", named.Obj().Name(), strings.Join(missing, ", ")),
+					Details: map[string]interface{}{
+						"type":    types.TypeString(named, nil),
+						"missing": missing,
+					},
+					Suggestion: "Handle the missing values, or add a default clause if they need no handling",
+					Analyzer:   "exhaustive",
+					Category:   "missing-enum-case",
+				}, switchStmt.Switch, switchStmt.Switch+token.Pos(len("switch"))))
+			}
+			return true
+		})
+	}
+
+	return violations
+}
+
+// missingEnumCases returns the enum constants, in declaration order, whose
+// values no case of the switch lists; a default clause handles them all
+func missingEnumCases(switchStmt *ast.SwitchStmt, named *types.Named, enum IsEnum, info *types.Info) []string {
+	var handled []constant.Value
+	for _, stmt := range switchStmt.Body.List {
+		clause, ok := stmt.(*ast.CaseClause)
+		if !ok {
+			continue
+		}
+		if clause.List == nil {
+			return nil
+		}
+		for _, expr := range clause.List {
+			if value := info.Types[expr].Value; value != nil {
+				handled = append(handled, value)
+			}
+		}
+	}
+
+	var missing []string
+	scope := named.Obj().Pkg().Scope()
+	for _, name := range enum.Values {
+		enumConst, ok := scope.Lookup(name).(*types.Const)
+		if !ok {
+			continue
+		}
+		found := false
+		for _, value := range handled {
+			if constant.Compare(value, token.EQL, enumConst.Val()) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			missing = append(missing, name)
+		}
+	}
+	return missing
+}
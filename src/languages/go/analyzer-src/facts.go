@@ -0,0 +1,357 @@
+package analyzer
+
+import (
+	"go/ast"
+	"go/types"
+	"sort"
+)
+
+// MayPanic marks a function that can panic, directly or through a call
+type MayPanic struct {
+	Via string `json:"via"` // "panic", or the full name of the panicking callee
+}
+
+// ClosesArgument marks a function that closes some of its parameters,
+// directly or by passing them to a function that does
+type ClosesArgument struct {
+	Params []int `json:"params"` // Indexes of the closed parameters, ascending
+}
+
+// IsEnum marks a defined type whose values are a declared set of constants
+type IsEnum struct {
+	Values []string `json:"values"` // Constant names in declaration order
+}
+
+func (*MayPanic) AFact()       {}
+func (*ClosesArgument) AFact() {}
+func (*IsEnum) AFact()         {}
+
+// funcSummary is what the fact passes need to know about one function body
+type funcSummary struct {
+	fn       *types.Func
+	panics   bool           // Calls the panic builtin
+	recovers bool           // Defers a function literal that calls recover
+	callees  []*types.Func  // Static callees, in call order
+	closes   map[int]bool   // Parameters closed with x.Close()
+	forwards []paramForward // Parameters passed on to other functions
+}
+
+// paramForward records a parameter passed as an argument of a call
+type paramForward struct {
+	callee   *types.Func
+	argument int
+	param    int
+}
+
+// funcSummaries summarizes every type-checked function declaration of the
+// parsed files, in file and declaration order. Calls in go statements and in
+// function literals that are not called in place are not followed.
+func (p *Parser) funcSummaries() []*funcSummary {
+	var summaries []*funcSummary
+
+	filePaths := make([]string, 0, len(p.files))
+	for filePath := range p.files {
+		filePaths = append(filePaths, filePath)
+	}
+	sort.Strings(filePaths)
+
+	for _, filePath := range filePaths {
+		typeInfo := p.TypeInfo(filePath)
+		if typeInfo == nil || typeInfo.Pkg == nil {
+			continue
+		}
+		for _, decl := range p.files[filePath].Decls {
+			funcDecl, ok := decl.(*ast.FuncDecl)
+			if !ok || funcDecl.Body == nil {
+				continue
+			}
+			fn, ok := typeInfo.Info.Defs[funcDecl.Name].(*types.Func)
+			if !ok {
+				continue
+			}
+			summaries = append(summaries, summarizeFunc(fn, funcDecl, typeInfo.Info))
+		}
+	}
+
+	return summaries
+}
+
+// summarizeFunc walks one function body
+func summarizeFunc(fn *types.Func, funcDecl *ast.FuncDecl, info *types.Info) *funcSummary {
+	summary := &funcSummary{fn: fn, closes: make(map[int]bool)}
+
+	params := make(map[types.Object]int)
+	index := 0
+	for _, field := range funcDecl.Type.Params.List {
+		if len(field.Names) == 0 {
+			index++
+			continue
+		}
+		for _, name := range field.Names {
+			if obj := info.Defs[name]; obj != nil {
+				params[obj] = index
+			}
+			index++
+		}
+	}
+	paramIndex := func(expr ast.Expr) (int, bool) {
+		ident, ok := unparen(expr).(*ast.Ident)
+		if !ok {
+			return 0, false
+		}
+		param, ok := params[info.Uses[ident]]
+		return param, ok
+	}
+
+	var walk func(root ast.Node)
+	walk = func(root ast.Node) {
+		ast.Inspect(root, func(n ast.Node) bool {
+			switch node := n.(type) {
+			case *ast.GoStmt, *ast.FuncLit:
+				return false
+			case *ast.DeferStmt:
+				if lit, ok := node.Call.Fun.(*ast.FuncLit); ok && callsBuiltin(lit.Body, info, "recover") {
+					summary.recovers = true
+				}
+			case *ast.CallExpr:
+				if lit, ok := unparen(node.Fun).(*ast.FuncLit); ok {
+					walk(lit.Body)
+				}
+				if builtin, ok := calleeObject(node, info).(*types.Builtin); ok && builtin.Name() == "panic" {
+					summary.panics = true
+				}
+				if selector, ok := unparen(node.Fun).(*ast.SelectorExpr); ok && selector.Sel.Name == "Close" && len(node.Args) == 0 {
+					if param, ok := paramIndex(selector.X); ok {
+						summary.closes[param] = true
+					}
+				}
+				if callee, ok := calleeObject(node, info).(*types.Func); ok {
+					summary.callees = append(summary.callees, callee)
+					for argument, arg := range node.Args {
+						if param, ok := paramIndex(arg); ok {
+							summary.forwards = append(summary.forwards, paramForward{callee: callee, argument: argument, param: param})
+						}
+					}
+				}
+			}
+			return true
+		})
+	}
+	walk(funcDecl.Body)
+
+	return summary
+}
+
+// funcObject returns the type-checked function declared by an entity
+func (p *Parser) funcObject(entity EntityInfo) *types.Func {
+	typeInfo := p.TypeInfo(entity.File)
+	funcDecl := p.funcDeclAt(entity.File, entity.namePos)
+	if typeInfo == nil || typeInfo.Pkg == nil || funcDecl == nil {
+		return nil
+	}
+	fn, _ := typeInfo.Info.Defs[funcDecl.Name].(*types.Func)
+	return fn
+}
+
+// typeObject returns the type-checked object of a declared type, or nil
+// without type information
+func (p *Parser) typeObject(entity EntityInfo) *types.TypeName {
+	typeInfo := p.TypeInfo(entity.File)
+	file, ok := p.files[entity.File]
+	if typeInfo == nil || typeInfo.Pkg == nil || !ok {
+		return nil
+	}
+	var typeName *types.TypeName
+	ast.Inspect(file, func(node ast.Node) bool {
+		if typeSpec, ok := node.(*ast.TypeSpec); ok && typeSpec.Name.Pos() == entity.namePos {
+			typeName, _ = typeInfo.Info.Defs[typeSpec.Name].(*types.TypeName)
+		}
+		return typeName == nil
+	})
+	return typeName
+}
+
+// runPanicFacts exports MayPanic for functions that call panic, or call a
+// function that may panic, without deferring a recover
+func runPanicFacts(pass *Pass) []Violation {
+	summaries := pass.Parser.funcSummaries()
+
+	for changed := true; changed; {
+		changed = false
+		for _, summary := range summaries {
+			if summary.recovers || pass.ImportObjectFact(summary.fn, new(MayPanic)) {
+				continue
+			}
+			via := ""
+			if summary.panics {
+				via = "panic"
+			}
+			for _, callee := range summary.callees {
+				if via != "" {
+					break
+				}
+				if pass.ImportObjectFact(callee, new(MayPanic)) || knownPanicking(callee) {
+					via = objectKey(callee)
+				}
+			}
+			if via != "" {
+				pass.ExportObjectFact(summary.fn, &MayPanic{Via: via})
+				changed = true
+			}
+		}
+	}
+
+	return nil
+}
+
+// panickingFuncs are standard library functions that panic by design, by
+// full name; Must helpers of other packages are only known through facts
+var panickingFuncs = map[string]bool{
+	"regexp.MustCompile":          true,
+	"regexp.MustCompilePOSIX":     true,
+	"text/template.Must":          true,
+	"html/template.Must":          true,
+	"net/netip.MustParseAddr":     true,
+	"net/netip.MustParseAddrPort": true,
+	"net/netip.MustParsePrefix":   true,
+	"log.Panic":                   true,
+	"log.Panicf":                  true,
+	"log.Panicln":                 true,
+	"(*log.Logger).Panic":         true,
+	"(*log.Logger).Panicf":        true,
+	"(*log.Logger).Panicln":       true,
+}
+
+// knownPanicking reports standard library functions that panic by design
+func knownPanicking(fn *types.Func) bool {
+	return panickingFuncs[fn.Origin().FullName()]
+}
+
+// runCloseFacts exports ClosesArgument for functions that close one of their
+// parameters, following parameters passed on to other closing functions
+func runCloseFacts(pass *Pass) []Violation {
+	summaries := pass.Parser.funcSummaries()
+
+	for changed := true; changed; {
+		changed = false
+		for _, summary := range summaries {
+			for _, forward := range summary.forwards {
+				if summary.closes[forward.param] {
+					continue
+				}
+				var calleeCloses ClosesArgument
+				if !pass.ImportObjectFact(forward.callee, &calleeCloses) {
+					continue
+				}
+				for _, param := range calleeCloses.Params {
+					if param == forward.argument {
+						summary.closes[forward.param] = true
+						changed = true
+					}
+				}
+			}
+			if len(summary.closes) == 0 {
+				continue
+			}
+
+			fact := &ClosesArgument{}
+			for param := range summary.closes {
+				fact.Params = append(fact.Params, param)
+			}
+			sort.Ints(fact.Params)
+			pass.ExportObjectFact(summary.fn, fact)
+		}
+	}
+
+	return nil
+}
+
+// runEnumFacts exports IsEnum for defined integer or string types with at
+// least two constants of the type declared in their own package
+func runEnumFacts(pass *Pass) []Violation {
+	seen := make(map[*types.Package]bool)
+
+	for _, filePaths := range pass.Parser.packageFiles() {
+		typeInfo := pass.Parser.TypeInfo(filePaths[0])
+		if typeInfo == nil || typeInfo.Pkg == nil || seen[typeInfo.Pkg] {
+			continue
+		}
+		seen[typeInfo.Pkg] = true
+
+		constants := make(map[*types.TypeName][]*types.Const)
+		for _, obj := range typeInfo.Info.Defs {
+			constant, ok := obj.(*types.Const)
+			if !ok || constant.Name() == "_" {
+				continue
+			}
+			named, ok := constant.Type().(*types.Named)
+			if !ok || named.Obj().Pkg() != typeInfo.Pkg {
+				continue
+			}
+			basic, ok := named.Underlying().(*types.Basic)
+			if !ok || basic.Info()&(types.IsInteger|types.IsString) == 0 {
+				continue
+			}
+			constants[named.Obj()] = append(constants[named.Obj()], constant)
+		}
+
+		for typeName, values := range constants {
+			if len(values) < 2 {
+				continue
+			}
+			sort.Slice(values, func(i, j int) bool {
+				return values[i].Pos() < values[j].Pos()
+			})
+			fact := &IsEnum{}
+			for _, value := range values {
+				fact.Values = append(fact.Values, value.Name())
+			}
+			pass.ExportObjectFact(typeName, fact)
+		}
+	}
+
+	return nil
+}
+
+// calleeObject returns the function or builtin a call invokes statically
+func calleeObject(call *ast.CallExpr, info *types.Info) types.Object {
+	fun := unparen(call.Fun)
+	switch index := fun.(type) {
+	case *ast.IndexExpr:
+		fun = index.X
+	case *ast.IndexListExpr:
+		fun = index.X
+	}
+	switch node := fun.(type) {
+	case *ast.Ident:
+		return info.Uses[node]
+	case *ast.SelectorExpr:
+		return info.Uses[node.Sel]
+	}
+	return nil
+}
+
+// callsBuiltin reports whether a node calls the named builtin
+func callsBuiltin(root ast.Node, info *types.Info, name string) bool {
+	found := false
+	ast.Inspect(root, func(n ast.Node) bool {
+		if call, ok := n.(*ast.CallExpr); ok {
+			if builtin, ok := calleeObject(call, info).(*types.Builtin); ok && builtin.Name() == name {
+				found = true
+			}
+		}
+		return !found
+	})
+	return found
+}
+
+// unparen strips enclosing parentheses from an expression
+func unparen(expr ast.Expr) ast.Expr {
+	for {
+		paren, ok := expr.(*ast.ParenExpr)
+		if !ok {
+			return expr
+		}
+		expr = paren.X
+	}
+}
@@ -0,0 +1,108 @@
+package analyzer
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"go/ast"
+	"go/token"
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+// fingerprint identifies a violation independently of its line number: it
+// hashes the analyzer, category, file (by package import path, so it does
+// not depend on where the code is checked out), enclosing entity and the
+// whitespace-normalized first line of the violation. Only the first line
+// is used, so edits elsewhere in a long span such as an import block keep it.
+func (p *Parser) fingerprint(violation Violation) string {
+	parts := []string{
+		violation.Analyzer,
+		violation.Category,
+		path.Join(p.ImportPathFor(violation.File), filepath.Base(violation.File)),
+		p.enclosingEntity(violation.File, violation.Line),
+		p.normalizedLine(violation.File, violation.Line),
+	}
+	if violation.Line == 0 {
+		// Without a position the message is all that tells findings apart
+		parts = append(parts, violation.Message)
+	}
+
+	hash := sha256.New()
+	for _, part := range parts {
+		hash.Write([]byte(part))
+		hash.Write([]byte{0})
+	}
+	return hex.EncodeToString(hash.Sum(nil))[:32]
+}
+
+// withFingerprints sets the fingerprint of every violation
+func (p *Parser) withFingerprints(violations []Violation) []Violation {
+	for index := range violations {
+		violations[index].Fingerprint = p.fingerprint(violations[index])
+	}
+	return violations
+}
+
+// enclosingEntity names the top-level declaration containing a line:
+// Type.Method for methods, the declared names for types and values
+func (p *Parser) enclosingEntity(filePath string, line int) string {
+	file, ok := p.files[filePath]
+	if !ok {
+		return ""
+	}
+	tokenFile := p.fileSet.File(file.Pos())
+	if tokenFile == nil || line < 1 || line > tokenFile.LineCount() {
+		return ""
+	}
+	pos := tokenFile.LineStart(line)
+
+	for _, decl := range file.Decls {
+		lineStart := tokenFile.LineStart(tokenFile.Line(decl.Pos()))
+		if pos < lineStart || pos >= decl.End() {
+			continue
+		}
+		switch node := decl.(type) {
+		case *ast.FuncDecl:
+			return enclosingFunctionName(file, node.Pos())
+		case *ast.GenDecl:
+			if node.Tok == token.IMPORT {
+				return "import"
+			}
+			var names []string
+			for _, spec := range node.Specs {
+				switch s := spec.(type) {
+				case *ast.TypeSpec:
+					names = append(names, s.Name.Name)
+				case *ast.ValueSpec:
+					for _, name := range s.Names {
+						names = append(names, name.Name)
+					}
+				}
+			}
+			return strings.Join(names, ",")
+		}
+	}
+	return ""
+}
+
+// normalizedLine returns a source line with runs of whitespace collapsed,
+// so reformatting does not change fingerprints
+func (p *Parser) normalizedLine(filePath string, line int) string {
+	file, ok := p.files[filePath]
+	src := p.sources[filePath]
+	if !ok || src == nil {
+		return ""
+	}
+	tokenFile := p.fileSet.File(file.Pos())
+	if tokenFile == nil || line < 1 || line > tokenFile.LineCount() {
+		return ""
+	}
+
+	from := tokenFile.Offset(tokenFile.LineStart(line))
+	to := len(src)
+	if line < tokenFile.LineCount() {
+		to = tokenFile.Offset(tokenFile.LineStart(line + 1))
+	}
+	return strings.Join(strings.Fields(string(src[from:to])), " ")
+}
@@ -0,0 +1,68 @@
+package analyzer
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"go/token"
+)
+
+// textEdit builds an edit replacing the source between two positions
+func (p *Parser) textEdit(from, to token.Pos, newText string) TextEdit {
+	start := p.fileSet.Position(from)
+	return TextEdit{
+		File:    start.Filename,
+		Offset:  start.Offset,
+		End:     p.fileSet.Position(to).Offset,
+		NewText: newText,
+		Hash:    p.contentHash(start.Filename),
+	}
+}
+
+// insertEdit builds an edit inserting text at a position
+func (p *Parser) insertEdit(pos token.Pos, text string) TextEdit {
+	return p.textEdit(pos, pos, text)
+}
+
+// lineRange widens from..to to whole lines, including the line break, when
+// nothing but whitespace shares those lines
+func (p *Parser) lineRange(from, to token.Pos) (token.Pos, token.Pos) {
+	tokenFile := p.fileSet.File(from)
+	if tokenFile == nil {
+		return from, to
+	}
+	src := p.sources[tokenFile.Name()]
+	start, end := tokenFile.Offset(from), tokenFile.Offset(to)
+	if end > len(src) {
+		return from, to
+	}
+
+	for start > 0 && (src[start-1] == ' ' || src[start-1] == '\t') {
+		start--
+	}
+	for end < len(src) && (src[end] == ' ' || src[end] == '\t' || src[end] == '\r') {
+		end++
+	}
+	if (start > 0 && src[start-1] != '\n') || (end < len(src) && src[end] != '\n') {
+		return from, to
+	}
+	if end < len(src) {
+		end++
+	}
+	return tokenFile.Pos(start), tokenFile.Pos(end)
+}
+
+// contentHash returns the SHA-256 of a parsed file's source
+func (p *Parser) contentHash(filePath string) string {
+	if hash, ok := p.hashes[filePath]; ok {
+		return hash
+	}
+	hash := hashContent(p.sources[filePath])
+	p.hashes[filePath] = hash
+	return hash
+}
+
+// hashContent returns the hex SHA-256 of content
+func hashContent(content []byte) string {
+	sum := sha256.Sum256(content)
+	return hex.EncodeToString(sum[:])
+}
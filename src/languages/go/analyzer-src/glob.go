@@ -0,0 +1,61 @@
+package analyzer
+
+import (
+	"regexp"
+	"strings"
+)
+
+// globCache memoizes compiled glob patterns
+var globCache = make(map[string]*regexp.Regexp)
+
+// matchGlob reports whether a slash-separated path matches a glob pattern.
+// "*" and "?" stay within one path element, while "**" and the Go-style
+// "..." match across elements, so "internal/**" and "./cmd/..." both work.
+func matchGlob(pattern, value string) bool {
+	compiled, ok := globCache[pattern]
+	if !ok {
+		compiled = compileGlob(pattern)
+		globCache[pattern] = compiled
+	}
+	return compiled.MatchString(value)
+}
+
+// matchAnyGlob reports whether value matches any of the patterns
+func matchAnyGlob(patterns []string, value string) bool {
+	for _, pattern := range patterns {
+		if matchGlob(pattern, value) {
+			return true
+		}
+	}
+	return false
+}
+
+// compileGlob translates a glob pattern into an anchored regular expression
+func compileGlob(pattern string) *regexp.Regexp {
+	var builder strings.Builder
+	builder.WriteString("^")
+	for i := 0; i < len(pattern); i++ {
+		switch {
+		case strings.HasPrefix(pattern[i:], "/**"):
+			builder.WriteString("(/.*)?")
+			i += 2
+		case strings.HasPrefix(pattern[i:], "**"):
+			builder.WriteString(".*")
+			i++
+		case strings.HasPrefix(pattern[i:], "/..."):
+			builder.WriteString("(/.*)?")
+			i += 3
+		case strings.HasPrefix(pattern[i:], "..."):
+			builder.WriteString(".*")
+			i += 2
+		case pattern[i] == '*':
+			builder.WriteString("[^/]*")
+		case pattern[i] == '?':
+			builder.WriteString("[^/]")
+		default:
+			builder.WriteString(regexp.QuoteMeta(pattern[i : i+1]))
+		}
+	}
+	builder.WriteString("$")
+	return regexp.MustCompile(builder.String())
+}
@@ -0,0 +1,69 @@
+package analyzer
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+var update = flag.Bool("update", false, "rewrite the golden files in testdata/golden")
+
+// samplesDir holds the repository's sample projects, relative to this package
+const samplesDir = "../../../../tests/samples"
+
+// goldenSamples are the sample projects whose results are pinned
+var goldenSamples = []string{"go-basic", "go-advanced"}
+
+// goldenOptions enables every analyzer that needs no project configuration
+var goldenOptions = AnalysisOptions{
+	Analyzers: []string{"solid", "imports", "errors", "goroutines", "channels", "dependencies", "naming"},
+}
+
+// TestGoldenResults compares the full result for each sample project with
+// testdata/golden/<sample>.json; run with -update to accept changes
+func TestGoldenResults(t *testing.T) {
+	for _, sample := range goldenSamples {
+		sample := sample
+		t.Run(sample, func(t *testing.T) {
+			files, err := filepath.Glob(filepath.Join(samplesDir, sample, "*.go"))
+			if err != nil || len(files) == 0 {
+				t.Fatalf("no Go files in sample %s: %v", sample, err)
+			}
+
+			result, err := NewAnalyzer(goldenOptions).Analyze(files)
+			if err != nil {
+				t.Fatalf("analysis failed: %v", err)
+			}
+			result.Metrics.ExecutionTime = 0
+
+			got, err := json.MarshalIndent(result, "", "  ")
+			if err != nil {
+				t.Fatalf("cannot marshal result: %v", err)
+			}
+			got = append(got, '\n')
+
+			goldenPath := filepath.Join("testdata", "golden", sample+".json")
+			if *update {
+				if err := os.MkdirAll(filepath.Dir(goldenPath), 0755); err != nil {
+					t.Fatal(err)
+				}
+				if err := os.WriteFile(goldenPath, got, 0644); err != nil {
+					t.Fatal(err)
+				}
+				return
+			}
+
+			want, err := os.ReadFile(goldenPath)
+			if err != nil {
+				t.Fatalf("cannot read golden file (run with -update to create it): %v", err)
+			}
+			if !bytes.Equal(got, want) {
+				t.Errorf("result differs from %s (run with -update to accept):\n%s",
+					goldenPath, unifiedDiff(filepath.ToSlash(goldenPath), want, got))
+			}
+		})
+	}
+}
@@ -0,0 +1,47 @@
+package analyzer
+
+import (
+	"fmt"
+	"strings"
+)
+
+// analyzeImportBan reports imports of rule.Module, or of any package below
+// it, from files and packages not listed in the rule's exceptions
+func (r *RuleAnalyzer) analyzeImportBan(rule Rule) []Violation {
+	var violations []Violation
+	if rule.Module == "" {
+		return violations
+	}
+
+	for filePath, file := range r.parser.files {
+		if r.isBoundaryException(rule, filePath, "") {
+			continue
+		}
+
+		for _, importSpec := range file.Imports {
+			importPath := importPathOf(importSpec)
+			if !importBanMatches(rule.Module, importPath) {
+				continue
+			}
+
+			details := map[string]interface{}{
+				"importSpecifier": importPath,
+				"module":          rule.Module,
+			}
+			violation := r.ruleViolation(rule, filePath, fmt.Sprintf("Import of %s is banned", importPath), details)
+			if rule.Replacement != "" {
+				details["replacement"] = rule.Replacement
+				violation.Suggestion = fmt.Sprintf("Use %s instead of %s", rule.Replacement, importPath)
+			}
+			violations = append(violations, r.parser.withRange(violation, importSpec.Path.Pos(), importSpec.Path.End()))
+		}
+	}
+
+	return violations
+}
+
+// importBanMatches reports whether an import path is the banned path, lies
+// below it, or matches it as a glob
+func importBanMatches(module, importPath string) bool {
+	return importPath == module || strings.HasPrefix(importPath, module+"/") || matchGlob(module, importPath)
+}
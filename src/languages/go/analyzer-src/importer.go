@@ -0,0 +1,148 @@
+package analyzer
+
+import (
+	"fmt"
+	"go/ast"
+	"go/build"
+	"go/importer"
+	"go/parser"
+	"go/types"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// localImporter type-checks packages that resolve to local module
+// directories from source, so cross-module imports inside a workspace or
+// through replace directives work without a build. Everything else is
+// delegated to the go/importer source importer.
+type localImporter struct {
+	parser   *Parser
+	fallback types.ImporterFrom
+	packages map[string]*types.Package
+	loading  map[string]bool
+}
+
+// newLocalImporter creates an importer bound to the parser's file set
+func newLocalImporter(p *Parser) *localImporter {
+	return &localImporter{
+		parser:   p,
+		fallback: importer.ForCompiler(p.fileSet, "source", nil).(types.ImporterFrom),
+		packages: make(map[string]*types.Package),
+		loading:  make(map[string]bool),
+	}
+}
+
+// Import implements types.Importer
+func (l *localImporter) Import(path string) (*types.Package, error) {
+	return l.ImportFrom(path, "", 0)
+}
+
+// ImportFrom implements types.ImporterFrom
+func (l *localImporter) ImportFrom(path, dir string, mode types.ImportMode) (*types.Package, error) {
+	if path == "unsafe" {
+		return types.Unsafe, nil
+	}
+	if pkg, ok := l.packages[path]; ok {
+		return pkg, nil
+	}
+
+	localDir, ok := l.parser.ResolveImportDir(dir, path)
+	if !ok {
+		return l.fallback.ImportFrom(path, dir, mode)
+	}
+
+	if l.loading[path] {
+		return nil, fmt.Errorf("import cycle through %s", path)
+	}
+	l.loading[path] = true
+	defer delete(l.loading, path)
+
+	pkg, err := l.checkDir(path, localDir)
+	if err != nil {
+		return nil, err
+	}
+	l.packages[path] = pkg
+	return pkg, nil
+}
+
+// checkDir type-checks the non-test files of a local package directory,
+// preferring already parsed files and overlay content over the disk
+func (l *localImporter) checkDir(path, dir string) (*types.Package, error) {
+	filePaths, err := localPackageFiles(dir)
+	if err != nil {
+		return nil, fmt.Errorf("cannot find package %s in %s: %w", path, dir, err)
+	}
+
+	var files []*ast.File
+	for _, filePath := range filePaths {
+		file, err := l.parseFile(filePath)
+		if err != nil {
+			return nil, err
+		}
+		files = append(files, file)
+	}
+	if len(files) == 0 {
+		return nil, fmt.Errorf("no Go files for package %s in %s", path, dir)
+	}
+
+	// Imported packages only need their exported API; body errors are tolerated
+	config := types.Config{
+		Importer:         l,
+		IgnoreFuncBodies: true,
+		Error:            func(error) {},
+	}
+	pkg, _ := config.Check(path, l.parser.fileSet, files, nil)
+	return pkg, nil
+}
+
+// parseFile returns the parsed file for filePath, reusing parsed or overlay content
+func (l *localImporter) parseFile(filePath string) (*ast.File, error) {
+	for parsedPath, file := range l.parser.files {
+		if samePath(parsedPath, filePath) {
+			return file, nil
+		}
+	}
+
+	src, err := l.parser.localSource(filePath)
+	if err != nil {
+		return nil, err
+	}
+	return parser.ParseFile(l.parser.fileSet, filePath, src, 0)
+}
+
+// localPackageFiles returns the non-test Go files of a local package
+// directory that match the build context, in name order
+func localPackageFiles(dir string) ([]string, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	var filePaths []string
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() || !strings.HasSuffix(name, ".go") || strings.HasSuffix(name, "_test.go") {
+			continue
+		}
+		if match, err := build.Default.MatchFile(dir, name); err == nil && match {
+			filePaths = append(filePaths, filepath.Join(dir, name))
+		}
+	}
+	return filePaths, nil
+}
+
+// localSource returns the overlay content of filePath, or its content on disk
+func (p *Parser) localSource(filePath string) ([]byte, error) {
+	if src, ok := p.overlayContent(filePath); ok {
+		return src, nil
+	}
+	return os.ReadFile(filePath)
+}
+
+// samePath reports whether two file paths refer to the same location
+func samePath(a, b string) bool {
+	absA, errA := filepath.Abs(a)
+	absB, errB := filepath.Abs(b)
+	return errA == nil && errB == nil && absA == absB
+}
@@ -0,0 +1,729 @@
+package analyzer
+
+import (
+	"fmt"
+	"go/ast"
+	"go/build"
+	"go/token"
+	"go/types"
+	"path"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// ImportAnalyzer analyzes import usage and organization
+type ImportAnalyzer struct {
+	parser *Parser
+}
+
+// importGroup is a run of import specs not separated by blank lines
+type importGroup []*ast.ImportSpec
+
+// majorVersionSuffix matches the /vN element of versioned module paths
+var majorVersionSuffix = regexp.MustCompile(`^v[0-9]+$`)
+
+// NewImportAnalyzer creates a new import analyzer
+func NewImportAnalyzer(parser *Parser) *ImportAnalyzer {
+	return &ImportAnalyzer{parser: parser}
+}
+
+// Analyze performs import analysis on all parsed files
+func (i *ImportAnalyzer) Analyze() []Violation {
+	var violations []Violation
+
+	for filePath, file := range i.parser.files {
+		if len(file.Imports) == 0 {
+			continue
+		}
+		violations = append(violations, i.analyzeImportCount(filePath, file)...)
+		violations = append(violations, i.analyzeDotImports(filePath, file)...)
+		violations = append(violations, i.analyzeUnusedImports(filePath, file)...)
+		violations = append(violations, i.analyzeDuplicateImports(filePath, file)...)
+		violations = append(violations, i.analyzeBlankImports(filePath, file)...)
+		violations = append(violations, i.analyzeImportGrouping(filePath, file)...)
+		violations = append(violations, i.analyzeShadowingAliases(filePath, file)...)
+	}
+
+	return violations
+}
+
+// analyzeImportCount flags files with many imports
+func (i *ImportAnalyzer) analyzeImportCount(filePath string, file *ast.File) []Violation {
+	if len(file.Imports) <= i.parser.threshold(filePath, "imports", "import-organization", "importCount") {
+		return nil
+	}
+
+	blockStart, blockEnd := importBlockRange(file)
+	return []Violation{i.parser.withRange(Violation{
+		File:     filePath,
+		Severity: "suggestion",
+		Message:  "File has many imports - consider organizing or reducing dependencies",
+		Details: map[string]interface{}{
+			"importCount": len(file.Imports),
+		},
+		Suggestion: "Group related imports and consider if all are necessary",
+		Analyzer:   "imports",
+		Category:   "import-organization",
+	}, blockStart, blockEnd)}
+}
+
+// analyzeDotImports flags dot imports (considered bad practice)
+func (i *ImportAnalyzer) analyzeDotImports(filePath string, file *ast.File) []Violation {
+	var violations []Violation
+
+	for _, importSpec := range file.Imports {
+		if importSpec.Name != nil && importSpec.Name.Name == "." {
+			violations = append(violations, i.parser.withRange(Violation{
+				File:     filePath,
+				Severity: "warning",
+				Message:  "Dot import detected - can lead to namespace pollution",
+				Details: map[string]interface{}{
+					"import": importSpec.Path.Value,
+				},
+				Fixes:      i.dotImportFixes(filePath, file, importSpec),
+				Suggestion: "Use explicit import names instead of dot imports",
+				Analyzer:   "imports",
+				Category:   "import-style",
+			}, importSpec.Pos(), importSpec.End()))
+		}
+	}
+
+	return violations
+}
+
+// dotImportFixes replaces a dot import with a named one and qualifies every
+// reference to the package; references are only known with type information.
+// There is no fix when the package name is already taken where it is needed.
+func (i *ImportAnalyzer) dotImportFixes(filePath string, file *ast.File, importSpec *ast.ImportSpec) []Fix {
+	typeInfo := i.parser.TypeInfo(filePath)
+	if typeInfo == nil {
+		return nil
+	}
+	// The dot identifier itself defines the package name
+	pkgName, ok := typeInfo.Info.Defs[importSpec.Name].(*types.PkgName)
+	if !ok {
+		return nil
+	}
+	imported := pkgName.Imported()
+
+	edits := []TextEdit{i.parser.textEdit(importSpec.Name.Pos(), importSpec.Path.Pos(), "")}
+	clash := false
+	ast.Inspect(file, func(n ast.Node) bool {
+		ident, ok := n.(*ast.Ident)
+		if !ok {
+			return true
+		}
+		obj := typeInfo.Info.Uses[ident]
+		if obj != nil && obj.Pkg() == imported && obj.Parent() == imported.Scope() {
+			clash = clash || packageNameTaken(typeInfo.Pkg, imported.Name(), ident.Pos())
+			edits = append(edits, i.parser.insertEdit(ident.Pos(), imported.Name()+"."))
+		}
+		return true
+	})
+	if clash {
+		return nil
+	}
+
+	return []Fix{{
+		Description: fmt.Sprintf("Import %s by name and qualify its identifiers", imported.Path()),
+		Edits:       edits,
+	}}
+}
+
+// packageNameTaken reports whether name already refers to something other
+// than a universe object at pos, such as another import, a package-level
+// declaration or a local variable, so a package imported by that name would
+// be shadowed there or clash with it
+func packageNameTaken(pkg *types.Package, name string, pos token.Pos) bool {
+	scope := pkg.Scope().Innermost(pos)
+	if scope == nil {
+		return pkg.Scope().Lookup(name) != nil
+	}
+	_, obj := scope.LookupParent(name, pos)
+	return obj != nil && obj.Parent() != types.Universe
+}
+
+// analyzeUnusedImports flags imports whose package name is never referenced
+func (i *ImportAnalyzer) analyzeUnusedImports(filePath string, file *ast.File) []Violation {
+	var violations []Violation
+
+	usedByTypes, typed := i.typedImportUsage(filePath, file)
+	usedNames := selectorQualifiers(file)
+	seen := make(map[string]bool)
+
+	for _, importSpec := range file.Imports {
+		importPath := importPathOf(importSpec)
+		if importPath == "C" || (importSpec.Name != nil && (importSpec.Name.Name == "_" || importSpec.Name.Name == ".")) {
+			continue
+		}
+		// Repeated paths are reported as duplicates instead
+		if seen[importPath] {
+			continue
+		}
+		seen[importPath] = true
+
+		var used, known bool
+		if typed {
+			used, known = usedByTypes[importSpec]
+		}
+		if !known {
+			name := localImportName(importSpec)
+			if name == "" {
+				continue // Package name cannot be guessed reliably
+			}
+			used = usedNames[name]
+		}
+		if used {
+			continue
+		}
+
+		violations = append(violations, i.parser.withRange(Violation{
+			File:     filePath,
+			Severity: "warning",
+			Message:  fmt.Sprintf("Import %q is not used", importPath),
+			Details: map[string]interface{}{
+				"import": importPath,
+			},
+			Fixes:      []Fix{i.removeImportFix(file, "Remove unused import", importSpec)},
+			Suggestion: "Remove the unused import",
+			Analyzer:   "imports",
+			Category:   "unused-import",
+		}, importSpec.Pos(), importSpec.End()))
+	}
+
+	return violations
+}
+
+// analyzeDuplicateImports flags the same path imported more than once
+func (i *ImportAnalyzer) analyzeDuplicateImports(filePath string, file *ast.File) []Violation {
+	var violations []Violation
+
+	seen := make(map[string]*ast.ImportSpec)
+	for _, importSpec := range file.Imports {
+		importPath := importPathOf(importSpec)
+		first, ok := seen[importPath]
+		if !ok {
+			seen[importPath] = importSpec
+			continue
+		}
+
+		violations = append(violations, i.parser.withRange(Violation{
+			File:     filePath,
+			Severity: "warning",
+			Message:  fmt.Sprintf("Package %q is imported more than once", importPath),
+			Details: map[string]interface{}{
+				"import":    importPath,
+				"firstLine": i.parser.fileSet.Position(first.Pos()).Line,
+			},
+			Fixes:      []Fix{i.removeImportFix(file, "Remove duplicate import", importSpec)},
+			Suggestion: "Import each package once and use a single name for it",
+			Analyzer:   "imports",
+			Category:   "duplicate-import",
+		}, importSpec.Pos(), importSpec.End()))
+	}
+
+	return violations
+}
+
+// analyzeBlankImports flags side-effect imports outside main and test packages
+func (i *ImportAnalyzer) analyzeBlankImports(filePath string, file *ast.File) []Violation {
+	if file.Name.Name == "main" || strings.HasSuffix(filePath, "_test.go") {
+		return nil
+	}
+
+	var violations []Violation
+	for _, importSpec := range file.Imports {
+		importPath := importPathOf(importSpec)
+		if importSpec.Name == nil || importSpec.Name.Name != "_" || importPath == "embed" {
+			continue
+		}
+		// A comment justifying the side effect is accepted
+		if importSpec.Doc != nil || importSpec.Comment != nil {
+			continue
+		}
+
+		violations = append(violations, i.parser.withRange(Violation{
+			File:     filePath,
+			Severity: "suggestion",
+			Message:  fmt.Sprintf("Blank import of %q in library package %s", importPath, file.Name.Name),
+			Details: map[string]interface{}{
+				"import":  importPath,
+				"package": file.Name.Name,
+			},
+			Fixes:      []Fix{i.removeImportFix(file, "Remove blank import", importSpec)},
+			Suggestion: "Move side-effect imports to package main or a test, or add a comment justifying them",
+			Analyzer:   "imports",
+			Category:   "blank-import",
+		}, importSpec.Pos(), importSpec.End()))
+	}
+
+	return violations
+}
+
+// analyzeImportGrouping checks imports are grouped std, third-party, then module-local
+func (i *ImportAnalyzer) analyzeImportGrouping(filePath string, file *ast.File) []Violation {
+	groups := i.importGroups(file)
+	if len(groups) == 0 {
+		return nil
+	}
+
+	problem := ""
+	lastRank := -1
+	for _, group := range groups {
+		rank := i.importRank(filePath, group[0])
+		for _, importSpec := range group[1:] {
+			if i.importRank(filePath, importSpec) != rank {
+				problem = "an import group mixes standard library, third-party and module-local packages"
+			}
+		}
+		if rank < lastRank && problem == "" {
+			problem = "import groups are not ordered standard library, third-party, module-local"
+		}
+		lastRank = rank
+	}
+	if problem == "" {
+		return nil
+	}
+
+	blockStart, blockEnd := importBlockRange(file)
+	return []Violation{i.parser.withRange(Violation{
+		File:     filePath,
+		Severity: "suggestion",
+		Message:  "Imports are not grouped by origin: " + problem,
+		Details: map[string]interface{}{
+			"module": i.parser.modulePathFor(filePath),
+		},
+		Fixes:      i.regroupImportsFixes(filePath, file),
+		Suggestion: "Group imports as standard library, then third-party, then module-local, separated by blank lines",
+		Analyzer:   "imports",
+		Category:   "import-grouping",
+	}, blockStart, blockEnd)}
+}
+
+// analyzeShadowingAliases flags import aliases that shadow other package names
+func (i *ImportAnalyzer) analyzeShadowingAliases(filePath string, file *ast.File) []Violation {
+	var violations []Violation
+
+	defaultNames := make(map[string]string)
+	for _, importSpec := range file.Imports {
+		if importSpec.Name == nil {
+			if name := localImportName(importSpec); name != "" {
+				defaultNames[name] = importPathOf(importSpec)
+			}
+		}
+	}
+
+	for _, importSpec := range file.Imports {
+		if importSpec.Name == nil || importSpec.Name.Name == "_" || importSpec.Name.Name == "." {
+			continue
+		}
+		alias := importSpec.Name.Name
+		importPath := importPathOf(importSpec)
+		if guessPackageName(importPath) == alias {
+			continue // Redundant but harmless
+		}
+
+		shadowed := defaultNames[alias]
+		if shadowed == "" && isStdPackage(alias) {
+			shadowed = alias
+		}
+		if shadowed == "" || shadowed == importPath {
+			continue
+		}
+
+		replacement := uniqueAlias(importPath, file)
+		violations = append(violations, i.parser.withRange(Violation{
+			File:     filePath,
+			Severity: "warning",
+			Message:  fmt.Sprintf("Import alias %q for %q shadows package %q", alias, importPath, shadowed),
+			Details: map[string]interface{}{
+				"import":   importPath,
+				"alias":    alias,
+				"shadowed": shadowed,
+			},
+			Fixes: []Fix{i.renameImportFix(filePath, file, fmt.Sprintf("Rename alias %s to %s", alias, replacement),
+				importSpec, replacement)},
+			Suggestion: "Choose an alias that does not collide with another package name",
+			Analyzer:   "imports",
+			Category:   "import-shadowing",
+		}, importSpec.Name.Pos(), importSpec.Name.End()))
+	}
+
+	return violations
+}
+
+// typedImportUsage reports, per import spec, whether go/types saw its package name used
+func (i *ImportAnalyzer) typedImportUsage(filePath string, file *ast.File) (map[*ast.ImportSpec]bool, bool) {
+	typeInfo := i.parser.TypeInfo(filePath)
+	if typeInfo == nil || typeInfo.Pkg == nil {
+		return nil, false
+	}
+
+	usedObjects := make(map[types.Object]bool)
+	for ident, obj := range typeInfo.Info.Uses {
+		if _, ok := obj.(*types.PkgName); ok && i.parser.fileSet.File(ident.Pos()) == i.parser.fileSet.File(file.Pos()) {
+			usedObjects[obj] = true
+		}
+	}
+
+	usage := make(map[*ast.ImportSpec]bool)
+	for _, importSpec := range file.Imports {
+		var obj types.Object
+		if importSpec.Name != nil {
+			obj = typeInfo.Info.Defs[importSpec.Name]
+		} else {
+			obj = typeInfo.Info.Implicits[importSpec]
+		}
+		if obj != nil {
+			usage[importSpec] = usedObjects[obj]
+		}
+	}
+	return usage, true
+}
+
+// importGroups splits the import declarations into blank-line separated
+// groups; the cgo import is not part of any group
+func (i *ImportAnalyzer) importGroups(file *ast.File) []importGroup {
+	var groups []importGroup
+
+	for _, decl := range file.Decls {
+		genDecl, ok := decl.(*ast.GenDecl)
+		if !ok || genDecl.Tok != token.IMPORT || isCgoDecl(genDecl) {
+			continue
+		}
+
+		var current importGroup
+		lastLine := 0
+		for _, spec := range genDecl.Specs {
+			importSpec := spec.(*ast.ImportSpec)
+			startPos := importSpec.Pos()
+			if importSpec.Doc != nil {
+				startPos = importSpec.Doc.Pos()
+			}
+			startLine := i.parser.fileSet.Position(startPos).Line
+			if len(current) > 0 && startLine > lastLine+1 {
+				groups = append(groups, current)
+				current = nil
+			}
+			current = append(current, importSpec)
+			lastLine = i.parser.fileSet.Position(importSpec.End()).Line
+		}
+		if len(current) > 0 {
+			groups = append(groups, current)
+		}
+	}
+
+	return groups
+}
+
+// importRank orders import classes: std, then third-party, then module-local
+func (i *ImportAnalyzer) importRank(filePath string, importSpec *ast.ImportSpec) int {
+	switch i.parser.ClassifyImport(filePath, importPathOf(importSpec)) {
+	case ImportStd:
+		return 0
+	case ImportModule:
+		return 2
+	default:
+		return 1
+	}
+}
+
+// removeImportFix deletes an import spec with its comments, or its whole
+// declaration when it is the only spec, leaving the other imports untouched
+func (i *ImportAnalyzer) removeImportFix(file *ast.File, description string, importSpec *ast.ImportSpec) Fix {
+	from, to := importSpec.Pos(), importSpec.End()
+	if importSpec.Doc != nil {
+		from = importSpec.Doc.Pos()
+	}
+	if decl := importDecl(file, importSpec); decl != nil && len(decl.Specs) == 1 {
+		from, to = decl.Pos(), decl.End()
+		if decl.Doc != nil {
+			from = decl.Doc.Pos()
+		}
+	}
+	if importSpec.Comment != nil && importSpec.Comment.End() > to {
+		to = importSpec.Comment.End()
+	}
+
+	from, to = i.parser.lineRange(from, to)
+	return Fix{
+		Description: description,
+		Edits:       []TextEdit{i.parser.textEdit(from, to, "")},
+	}
+}
+
+// renameImportFix renames an import's alias and every reference to it
+func (i *ImportAnalyzer) renameImportFix(filePath string, file *ast.File, description string, importSpec *ast.ImportSpec, alias string) Fix {
+	edits := []TextEdit{i.parser.textEdit(importSpec.Name.Pos(), importSpec.Name.End(), alias)}
+	for _, ident := range i.importReferences(filePath, file, importSpec) {
+		edits = append(edits, i.parser.textEdit(ident.Pos(), ident.End(), alias))
+	}
+
+	return Fix{
+		Description: description,
+		Edits:       edits,
+	}
+}
+
+// regroupImportsFixes rewrites the import declarations as one block grouped
+// by origin. The cgo import keeps its own declaration next to its preamble,
+// so no fix is offered when it sits between other imports. Standalone
+// comments move with the import that follows them.
+func (i *ImportAnalyzer) regroupImportsFixes(filePath string, file *ast.File) []Fix {
+	var start, end token.Pos
+	cgoPos := token.NoPos
+	for _, decl := range file.Decls {
+		genDecl, ok := decl.(*ast.GenDecl)
+		if !ok || genDecl.Tok != token.IMPORT {
+			continue
+		}
+		if isCgoDecl(genDecl) {
+			cgoPos = genDecl.Pos()
+			continue
+		}
+		if !start.IsValid() {
+			start = genDecl.Pos()
+		}
+		end = genDecl.End()
+	}
+	if !start.IsValid() || (cgoPos > start && cgoPos < end) {
+		return nil
+	}
+
+	// Comments attached to a spec are rendered with it
+	attached := make(map[*ast.CommentGroup]bool)
+	var specs []*ast.ImportSpec
+	for _, group := range i.importGroups(file) {
+		for _, importSpec := range group {
+			attached[importSpec.Doc] = true
+			attached[importSpec.Comment] = true
+			specs = append(specs, importSpec)
+		}
+	}
+	leading := make(map[*ast.ImportSpec][]string)
+	var trailing []string
+	for _, comments := range file.Comments {
+		if comments.Pos() < start || comments.End() > end || attached[comments] {
+			continue
+		}
+		var lines []string
+		for _, comment := range comments.List {
+			lines = append(lines, comment.Text)
+		}
+		next := sort.Search(len(specs), func(n int) bool { return specs[n].Pos() > comments.End() })
+		if next == len(specs) {
+			trailing = append(trailing, lines...)
+			continue
+		}
+		leading[specs[next]] = append(leading[specs[next]], lines...)
+	}
+
+	byRank := make([]importGroup, 3)
+	for _, importSpec := range specs {
+		rank := i.importRank(filePath, importSpec)
+		byRank[rank] = append(byRank[rank], importSpec)
+	}
+
+	var lines []string
+	for _, group := range byRank {
+		if len(group) == 0 {
+			continue
+		}
+		sort.SliceStable(group, func(a, b int) bool {
+			return importPathOf(group[a]) < importPathOf(group[b])
+		})
+		if len(lines) > 0 {
+			lines = append(lines, "")
+		}
+		for _, importSpec := range group {
+			lines = append(lines, leading[importSpec]...)
+			lines = append(lines, i.renderImportSpec(importSpec, "")...)
+		}
+	}
+	if len(trailing) > 0 {
+		lines = append(lines, "")
+		lines = append(lines, trailing...)
+	}
+
+	newText := "import " + lines[0]
+	if len(lines) > 1 {
+		newText = "import (\n\t" + strings.Join(lines, "\n\t") + "\n)"
+		newText = strings.Replace(newText, "\n\t\n", "\n\n", -1)
+	}
+
+	return []Fix{{
+		Description: "Regroup imports",
+		Edits:       []TextEdit{i.parser.textEdit(start, end, newText)},
+	}}
+}
+
+// importReferences returns the identifiers that refer to an import's package name
+func (i *ImportAnalyzer) importReferences(filePath string, file *ast.File, importSpec *ast.ImportSpec) []*ast.Ident {
+	var refs []*ast.Ident
+
+	if typeInfo := i.parser.TypeInfo(filePath); typeInfo != nil && importSpec.Name != nil {
+		if obj := typeInfo.Info.Defs[importSpec.Name]; obj != nil {
+			ast.Inspect(file, func(n ast.Node) bool {
+				if ident, ok := n.(*ast.Ident); ok && typeInfo.Info.Uses[ident] == obj {
+					refs = append(refs, ident)
+				}
+				return true
+			})
+			return refs
+		}
+	}
+
+	name := localImportName(importSpec)
+	ast.Inspect(file, func(n ast.Node) bool {
+		if selector, ok := n.(*ast.SelectorExpr); ok {
+			if ident, ok := selector.X.(*ast.Ident); ok && ident.Name == name {
+				refs = append(refs, ident)
+			}
+		}
+		return true
+	})
+	return refs
+}
+
+// renderImportSpec renders one import spec, with its comments, as source lines
+func (i *ImportAnalyzer) renderImportSpec(importSpec *ast.ImportSpec, alias string) []string {
+	var lines []string
+	if importSpec.Doc != nil {
+		for _, comment := range importSpec.Doc.List {
+			lines = append(lines, comment.Text)
+		}
+	}
+
+	line := importSpec.Path.Value
+	if alias == "" && importSpec.Name != nil {
+		alias = importSpec.Name.Name
+	}
+	if alias != "" {
+		line = alias + " " + line
+	}
+	if importSpec.Comment != nil {
+		for _, comment := range importSpec.Comment.List {
+			line += " " + comment.Text
+		}
+	}
+
+	return append(lines, line)
+}
+
+// importPathOf returns the unquoted path of an import spec
+func importPathOf(importSpec *ast.ImportSpec) string {
+	importPath, err := strconv.Unquote(importSpec.Path.Value)
+	if err != nil {
+		return importSpec.Path.Value
+	}
+	return importPath
+}
+
+// localImportName returns the name an import is referenced by, or "" when unknown
+func localImportName(importSpec *ast.ImportSpec) string {
+	if importSpec.Name != nil {
+		return importSpec.Name.Name
+	}
+	return guessPackageName(importPathOf(importSpec))
+}
+
+// guessPackageName derives the conventional package name from an import path,
+// returning "" when the last element is not a valid identifier
+func guessPackageName(importPath string) string {
+	name := path.Base(importPath)
+	if majorVersionSuffix.MatchString(name) && strings.Contains(importPath, "/") {
+		name = path.Base(path.Dir(importPath))
+	}
+	if idx := strings.Index(name, ".v"); idx > 0 {
+		name = name[:idx] // gopkg.in/yaml.v3
+	}
+	name = strings.TrimPrefix(name, "go-")
+	name = strings.TrimSuffix(name, "-go")
+	if !token.IsIdentifier(name) {
+		return ""
+	}
+	return name
+}
+
+// selectorQualifiers collects the identifiers used as selector qualifiers (pkg.Name)
+func selectorQualifiers(file *ast.File) map[string]bool {
+	names := make(map[string]bool)
+	ast.Inspect(file, func(n ast.Node) bool {
+		if selector, ok := n.(*ast.SelectorExpr); ok {
+			if ident, ok := selector.X.(*ast.Ident); ok {
+				names[ident.Name] = true
+			}
+		}
+		return true
+	})
+	return names
+}
+
+// isStdPackage reports whether a standard library package exists at importPath
+func isStdPackage(importPath string) bool {
+	pkg, err := build.Default.Import(importPath, "", build.FindOnly)
+	return err == nil && pkg.Goroot
+}
+
+// uniqueAlias proposes an alias for importPath not used as a qualifier in file
+func uniqueAlias(importPath string, file *ast.File) string {
+	base := guessPackageName(importPath)
+	if base == "" {
+		base = "pkg"
+	}
+	used := selectorQualifiers(file)
+	for _, importSpec := range file.Imports {
+		used[localImportName(importSpec)] = true
+	}
+
+	candidate := base + "pkg"
+	for n := 2; used[candidate] || isStdPackage(candidate); n++ {
+		candidate = fmt.Sprintf("%spkg%d", base, n)
+	}
+	return candidate
+}
+
+// importBlockRange returns the span covering all import declarations of a file
+func importBlockRange(file *ast.File) (token.Pos, token.Pos) {
+	var start, end token.Pos
+	for _, decl := range file.Decls {
+		genDecl, ok := decl.(*ast.GenDecl)
+		if !ok || genDecl.Tok != token.IMPORT {
+			continue
+		}
+		if !start.IsValid() {
+			start = genDecl.Pos()
+		}
+		end = genDecl.End()
+	}
+	return start, end
+}
+
+// importDecl returns the import declaration holding importSpec
+func importDecl(file *ast.File, importSpec *ast.ImportSpec) *ast.GenDecl {
+	for _, decl := range file.Decls {
+		genDecl, ok := decl.(*ast.GenDecl)
+		if !ok || genDecl.Tok != token.IMPORT {
+			continue
+		}
+		for _, spec := range genDecl.Specs {
+			if spec == importSpec {
+				return genDecl
+			}
+		}
+	}
+	return nil
+}
+
+// isCgoDecl reports whether an import declaration imports "C", whose doc
+// comment is the cgo preamble
+func isCgoDecl(genDecl *ast.GenDecl) bool {
+	for _, spec := range genDecl.Specs {
+		if importPathOf(spec.(*ast.ImportSpec)) == "C" {
+			return true
+		}
+	}
+	return false
+}
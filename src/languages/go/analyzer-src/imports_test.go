@@ -0,0 +1,105 @@
+package analyzer
+
+import "testing"
+
+func TestDotImportFixes(t *testing.T) {
+	tests := []struct {
+		name   string
+		source string
+		want   string // Fixed source; "" when no fix is offered
+	}{
+		{
+			name: "qualified references",
+			source: `package sample
+
+import . "strings"
+
+func upper(s string) string { return ToUpper(TrimSpace(s)) }
+`,
+			want: `package sample
+
+import "strings"
+
+func upper(s string) string { return strings.ToUpper(strings.TrimSpace(s)) }
+`,
+		},
+		{
+			name: "local variable in another function",
+			source: `package sample
+
+import . "strings"
+
+func upper(s string) string { return ToUpper(s) }
+
+func count(strings []string) int { return len(strings) }
+`,
+			want: `package sample
+
+import "strings"
+
+func upper(s string) string { return strings.ToUpper(s) }
+
+func count(strings []string) int { return len(strings) }
+`,
+		},
+		{
+			name: "local variable in scope of a reference",
+			source: `package sample
+
+import . "strings"
+
+func upper(strings []string) string { return ToUpper(strings[0]) }
+`,
+		},
+		{
+			name: "package-level declaration",
+			source: `package sample
+
+import . "strings"
+
+var strings = []string{"a"}
+
+func upper() string { return ToUpper(strings[0]) }
+`,
+		},
+		{
+			name: "another import by that name",
+			source: `package sample
+
+import (
+	strings "bytes"
+	. "strings"
+)
+
+var empty = strings.NewBuffer(nil)
+
+func upper(s string) string { return ToUpper(s) }
+`,
+		},
+	}
+
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			result := analyzeSource(t, test.source, "imports")
+
+			var fixes []Fix
+			for _, violation := range result.Violations {
+				if violation.Category == "import-style" {
+					fixes = append(fixes, violation.Fixes...)
+				}
+			}
+			switch {
+			case test.want == "" && len(fixes) != 0:
+				t.Errorf("unexpected fix %+v", fixes)
+			case test.want == "":
+			case len(fixes) != 1:
+				t.Errorf("got %d fixes, want 1", len(fixes))
+			default:
+				if got := string(applyEdits([]byte(test.source), fixes[0].Edits)); got != test.want {
+					t.Errorf("fixed source:\n%s\nwant:\n%s", got, test.want)
+				}
+			}
+		})
+	}
+}
@@ -66,6 +66,8 @@ func (i *Indexer) createFunctionIndexEntry(function Function) IndexEntry {
 			"returnType":   function.ReturnType,
 			"complexity":   function.Complexity,
 			"package":      function.Package,
+			"importPath":   function.ImportPath,
+			"module":       function.Module,
 			"dependencies": function.Dependencies,
 		},
 	}
@@ -85,11 +87,17 @@ func (i *Indexer) createStructIndexEntry(structInfo Struct) IndexEntry {
 		StartLine: structInfo.StartLine,
 		EndLine:   structInfo.EndLine,
 		Metadata: map[string]interface{}{
-			"isExported": structInfo.IsExported,
-			"fieldCount": len(structInfo.Fields),
-			"fields":     i.serializeFields(structInfo.Fields),
-			"methods":    structInfo.Methods,
-			"package":    structInfo.Package,
+			"isExported":       structInfo.IsExported,
+			"fieldCount":       len(structInfo.Fields),
+			"fields":           i.serializeFields(structInfo.Fields),
+			"methods":          structInfo.Methods,
+			"package":          structInfo.Package,
+			"importPath":       structInfo.ImportPath,
+			"module":           structInfo.Module,
+			"promotedMethods":  structInfo.PromotedMethods,
+			"embeds":           structInfo.Embeds,
+			"implements":       structInfo.Implements,
+			"implementsSource": structInfo.ImplementsSource,
 		},
 	}
 }
@@ -108,10 +116,14 @@ func (i *Indexer) createInterfaceIndexEntry(interfaceInfo Interface) IndexEntry
 		StartLine: interfaceInfo.StartLine,
 		EndLine:   interfaceInfo.EndLine,
 		Metadata: map[string]interface{}{
-			"isExported":   interfaceInfo.IsExported,
-			"methodCount":  len(interfaceInfo.Methods),
-			"methods":      i.serializeMethods(interfaceInfo.Methods),
-			"package":      interfaceInfo.Package,
+			"isExported":    interfaceInfo.IsExported,
+			"methodCount":   len(interfaceInfo.Methods),
+			"methods":       i.serializeMethods(interfaceInfo.Methods),
+			"package":       interfaceInfo.Package,
+			"importPath":    interfaceInfo.ImportPath,
+			"module":        interfaceInfo.Module,
+			"embeds":        interfaceInfo.Embeds,
+			"implementedBy": interfaceInfo.ImplementedBy,
 		},
 	}
 }
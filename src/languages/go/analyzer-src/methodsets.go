@@ -0,0 +1,351 @@
+package analyzer
+
+import (
+	"go/ast"
+	"go/token"
+	"go/types"
+	"sort"
+	"strings"
+)
+
+// typeRelation holds the method set, embeddings and interface satisfaction
+// computed for one named struct or interface type
+type typeRelation struct {
+	Methods         []string
+	PromotedMethods []string
+	Embeds          []string
+	Implements      []string
+	ImplementedBy   []string
+	Source          string // "types" when resolved by go/types, "methodset" otherwise
+}
+
+// typeDecl is the syntactic view of a named type used for method set matching
+type typeDecl struct {
+	name       string
+	isStruct   bool
+	embeds     []string
+	methodKeys []string // explicit interface methods as name+signature keys
+}
+
+// declaredMethod is a method declared with a receiver of a named type
+type declaredMethod struct {
+	name    string
+	key     string
+	pointer bool
+}
+
+// typeRelationFor returns the relations computed for a type declared in the given file
+func (p *Parser) typeRelationFor(filePath string, file *ast.File, typeName string) *typeRelation {
+	if p.relations == nil {
+		p.relations = make(map[packageKey]map[string]*typeRelation)
+		for key, paths := range p.packageFiles() {
+			p.relations[key] = p.computeTypeRelations(paths)
+		}
+	}
+	return p.relations[packageKeyFor(filePath, file)][typeName]
+}
+
+// computeTypeRelations builds type relations for the files of one package
+func (p *Parser) computeTypeRelations(paths []string) map[string]*typeRelation {
+	decls := make(map[string]*typeDecl)
+	var order []string
+	methods := make(map[string][]declaredMethod)
+
+	for _, path := range paths {
+		for _, decl := range p.files[path].Decls {
+			switch node := decl.(type) {
+			case *ast.GenDecl:
+				if node.Tok != token.TYPE {
+					continue
+				}
+				for _, spec := range node.Specs {
+					typeSpec, ok := spec.(*ast.TypeSpec)
+					if !ok {
+						continue
+					}
+					if info := newTypeDecl(typeSpec); info != nil {
+						decls[info.name] = info
+						order = append(order, info.name)
+					}
+				}
+			case *ast.FuncDecl:
+				if node.Recv == nil || len(node.Recv.List) == 0 {
+					continue
+				}
+				recvName, pointer := receiverTypeName(node.Recv.List[0].Type)
+				if recvName == "" {
+					continue
+				}
+				methods[recvName] = append(methods[recvName], declaredMethod{
+					name:    node.Name.Name,
+					key:     signatureKey(node.Name.Name, node.Type),
+					pointer: pointer,
+				})
+			}
+		}
+	}
+
+	relations := make(map[string]*typeRelation)
+	for _, name := range order {
+		decl := decls[name]
+		relation := &typeRelation{Embeds: decl.embeds, Source: "methodset"}
+		if decl.isStruct {
+			for _, method := range methods[name] {
+				relation.Methods = append(relation.Methods, method.name)
+			}
+		}
+		relations[name] = relation
+	}
+
+	// Method set matching over the syntax tree
+	for _, structName := range order {
+		structDecl := decls[structName]
+		if !structDecl.isStruct {
+			continue
+		}
+		own := make(map[string]bool)
+		for _, method := range methods[structName] {
+			own[method.key] = true
+		}
+		methodSet := structMethodSet(structName, decls, methods, map[string]bool{})
+		for key := range methodSet {
+			if !own[key] {
+				relations[structName].PromotedMethods = append(relations[structName].PromotedMethods, methodKeyName(key))
+			}
+		}
+		sort.Strings(relations[structName].PromotedMethods)
+
+		for _, ifaceName := range order {
+			ifaceDecl := decls[ifaceName]
+			if ifaceDecl.isStruct {
+				continue
+			}
+			required, complete := interfaceMethodSet(ifaceName, decls, map[string]bool{})
+			if !complete || len(required) == 0 {
+				continue
+			}
+			if containsAllKeys(methodSet, required) {
+				relations[structName].Implements = append(relations[structName].Implements, ifaceName)
+			}
+		}
+	}
+
+	if len(paths) > 0 {
+		if typeInfo := p.TypeInfo(paths[0]); typeInfo.Complete() {
+			applyTypeCheckedRelations(typeInfo.Pkg, relations)
+		}
+	}
+
+	for _, structName := range order {
+		for _, ifaceName := range relations[structName].Implements {
+			relations[ifaceName].ImplementedBy = append(relations[ifaceName].ImplementedBy, structName)
+		}
+	}
+
+	return relations
+}
+
+// applyTypeCheckedRelations replaces method set matching results with go/types answers
+func applyTypeCheckedRelations(pkg *types.Package, relations map[string]*typeRelation) {
+	scope := pkg.Scope()
+	var structs, ifaces []*types.TypeName
+	for _, name := range scope.Names() {
+		typeName, ok := scope.Lookup(name).(*types.TypeName)
+		if !ok || relations[name] == nil {
+			continue
+		}
+		named, ok := typeName.Type().(*types.Named)
+		if !ok || named.TypeParams().Len() > 0 {
+			continue
+		}
+		switch underlying := named.Underlying().(type) {
+		case *types.Struct:
+			structs = append(structs, typeName)
+		case *types.Interface:
+			if underlying.NumMethods() > 0 {
+				ifaces = append(ifaces, typeName)
+			}
+		}
+	}
+
+	for _, structType := range structs {
+		relation := relations[structType.Name()]
+		relation.Source = "types"
+		relation.Implements = nil
+		relation.PromotedMethods = nil
+
+		pointer := types.NewPointer(structType.Type())
+		methodSet := types.NewMethodSet(pointer)
+		for i := 0; i < methodSet.Len(); i++ {
+			if selection := methodSet.At(i); len(selection.Index()) > 1 {
+				relation.PromotedMethods = append(relation.PromotedMethods, selection.Obj().Name())
+			}
+		}
+
+		for _, iface := range ifaces {
+			if types.Implements(pointer, iface.Type().Underlying().(*types.Interface)) {
+				relation.Implements = append(relation.Implements, iface.Name())
+			}
+		}
+	}
+	for _, iface := range ifaces {
+		relations[iface.Name()].Source = "types"
+	}
+}
+
+// newTypeDecl builds the syntactic view of a struct or interface type spec
+func newTypeDecl(typeSpec *ast.TypeSpec) *typeDecl {
+	switch t := typeSpec.Type.(type) {
+	case *ast.StructType:
+		decl := &typeDecl{name: typeSpec.Name.Name, isStruct: true}
+		if t.Fields != nil {
+			for _, field := range t.Fields.List {
+				if len(field.Names) == 0 {
+					decl.embeds = append(decl.embeds, types.ExprString(field.Type))
+				}
+			}
+		}
+		return decl
+	case *ast.InterfaceType:
+		decl := &typeDecl{name: typeSpec.Name.Name}
+		if t.Methods != nil {
+			for _, method := range t.Methods.List {
+				if funcType, ok := method.Type.(*ast.FuncType); ok && len(method.Names) > 0 {
+					for _, name := range method.Names {
+						decl.methodKeys = append(decl.methodKeys, signatureKey(name.Name, funcType))
+					}
+				} else {
+					decl.embeds = append(decl.embeds, types.ExprString(method.Type))
+				}
+			}
+		}
+		return decl
+	}
+	return nil
+}
+
+// structMethodSet returns the pointer method set of a struct including promoted methods
+func structMethodSet(name string, decls map[string]*typeDecl, methods map[string][]declaredMethod, visited map[string]bool) map[string]bool {
+	set := make(map[string]bool)
+	if visited[name] {
+		return set
+	}
+	visited[name] = true
+
+	for _, method := range methods[name] {
+		set[method.key] = true
+	}
+
+	decl := decls[name]
+	if decl == nil {
+		return set
+	}
+	for _, embed := range decl.embeds {
+		embedded := decls[strings.TrimPrefix(embed, "*")]
+		if embedded == nil {
+			continue
+		}
+		var promoted map[string]bool
+		if embedded.isStruct {
+			promoted = structMethodSet(embedded.name, decls, methods, visited)
+		} else {
+			keys, _ := interfaceMethodSet(embedded.name, decls, map[string]bool{})
+			promoted = make(map[string]bool)
+			for _, key := range keys {
+				promoted[key] = true
+			}
+		}
+		for key := range promoted {
+			set[key] = true
+		}
+	}
+
+	return set
+}
+
+// interfaceMethodSet returns the methods required by an interface and whether
+// every embedded interface could be resolved locally
+func interfaceMethodSet(name string, decls map[string]*typeDecl, visited map[string]bool) ([]string, bool) {
+	decl := decls[name]
+	if decl == nil || decl.isStruct || visited[name] {
+		return nil, false
+	}
+
+	// visited holds the interfaces on the current embedding path only, so an
+	// interface reached twice through sibling embeds still contributes
+	visited[name] = true
+	defer delete(visited, name)
+
+	keys := append([]string{}, decl.methodKeys...)
+	complete := true
+	for _, embed := range decl.embeds {
+		embeddedKeys, ok := interfaceMethodSet(embed, decls, visited)
+		if !ok {
+			complete = false
+			continue
+		}
+		keys = append(keys, embeddedKeys...)
+	}
+
+	return keys, complete
+}
+
+// receiverTypeName returns the base type name of a method receiver and whether it is a pointer
+func receiverTypeName(expr ast.Expr) (string, bool) {
+	pointer := false
+	if star, ok := expr.(*ast.StarExpr); ok {
+		pointer = true
+		expr = star.X
+	}
+	switch t := expr.(type) {
+	case *ast.IndexExpr:
+		expr = t.X
+	case *ast.IndexListExpr:
+		expr = t.X
+	}
+	if ident, ok := expr.(*ast.Ident); ok {
+		return ident.Name, pointer
+	}
+	return "", pointer
+}
+
+// signatureKey normalizes a method name and signature for method set comparison
+func signatureKey(name string, funcType *ast.FuncType) string {
+	return name + fieldListKey(funcType.Params) + fieldListKey(funcType.Results)
+}
+
+// fieldListKey renders the types of a parameter or result list
+func fieldListKey(list *ast.FieldList) string {
+	var parts []string
+	if list != nil {
+		for _, field := range list.List {
+			fieldType := types.ExprString(field.Type)
+			count := len(field.Names)
+			if count == 0 {
+				count = 1
+			}
+			for i := 0; i < count; i++ {
+				parts = append(parts, fieldType)
+			}
+		}
+	}
+	return "(" + strings.Join(parts, ",") + ")"
+}
+
+// methodKeyName extracts the method name from a signature key
+func methodKeyName(key string) string {
+	if idx := strings.Index(key, "("); idx >= 0 {
+		return key[:idx]
+	}
+	return key
+}
+
+// containsAllKeys reports whether set contains every key
+func containsAllKeys(set map[string]bool, keys []string) bool {
+	for _, key := range keys {
+		if !set[key] {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,310 @@
+package analyzer
+
+import (
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// Import classes returned by ClassifyImport
+const (
+	ImportStd      = "std"      // Standard library
+	ImportModule   = "module"   // Same module as the importing file
+	ImportLocal    = "local"    // Another module available on disk (go.work or replace)
+	ImportExternal = "external" // Third-party dependency
+)
+
+// Module describes a Go module discovered from a go.mod file
+type Module struct {
+	Path      string
+	Dir       string
+	GoVersion string
+	Replace   map[string]string // Module path -> local directory
+}
+
+// Workspace describes a go.work file and the modules it uses
+type Workspace struct {
+	Dir     string
+	Modules []*Module
+	Replace map[string]string
+}
+
+// ModuleFor returns the module containing filePath, or nil outside any module
+func (p *Parser) ModuleFor(filePath string) *Module {
+	dir, err := filepath.Abs(filepath.Dir(filePath))
+	if err != nil {
+		return nil
+	}
+	return p.moduleForDir(dir)
+}
+
+// moduleForDir walks up from dir to the nearest go.mod, caching every step
+func (p *Parser) moduleForDir(dir string) *Module {
+	if module, ok := p.modules[dir]; ok {
+		return module
+	}
+
+	var module *Module
+	if data, err := os.ReadFile(filepath.Join(dir, "go.mod")); err == nil {
+		module = parseGoMod(dir, string(data))
+	} else if parent := filepath.Dir(dir); parent != dir {
+		module = p.moduleForDir(parent)
+	}
+
+	p.modules[dir] = module
+	return module
+}
+
+// WorkspaceFor returns the go.work workspace that includes module, honoring GOWORK
+func (p *Parser) WorkspaceFor(module *Module) *Workspace {
+	if module == nil {
+		return nil
+	}
+	if workspace, ok := p.workspaces[module.Dir]; ok {
+		return workspace
+	}
+
+	var workspace *Workspace
+	switch gowork := os.Getenv("GOWORK"); gowork {
+	case "off":
+	case "":
+		for dir := module.Dir; ; dir = filepath.Dir(dir) {
+			if data, err := os.ReadFile(filepath.Join(dir, "go.work")); err == nil {
+				workspace = p.parseGoWork(dir, string(data))
+				break
+			}
+			if filepath.Dir(dir) == dir {
+				break
+			}
+		}
+	default:
+		if data, err := os.ReadFile(gowork); err == nil {
+			workspace = p.parseGoWork(filepath.Dir(gowork), string(data))
+		}
+	}
+
+	// A go.work only applies to the modules it lists
+	if workspace != nil && !workspace.uses(module) {
+		workspace = nil
+	}
+
+	p.workspaces[module.Dir] = workspace
+	return workspace
+}
+
+// ImportPathFor returns the import path of the package containing filePath
+func (p *Parser) ImportPathFor(filePath string) string {
+	module := p.ModuleFor(filePath)
+	if module == nil {
+		if file, ok := p.files[filePath]; ok {
+			return file.Name.Name
+		}
+		return filepath.Base(filepath.Dir(filePath))
+	}
+
+	dir, _ := filepath.Abs(filepath.Dir(filePath))
+	rel, err := filepath.Rel(module.Dir, dir)
+	if err != nil || rel == "." {
+		return module.Path
+	}
+	return module.Path + "/" + filepath.ToSlash(rel)
+}
+
+// modulePathFor returns the module path of filePath, or "" outside any module
+func (p *Parser) modulePathFor(filePath string) string {
+	if module := p.ModuleFor(filePath); module != nil {
+		return module.Path
+	}
+	return ""
+}
+
+// PackageAndModuleCounts counts the distinct packages and modules among the
+// reported (non-context) files
+func (p *Parser) PackageAndModuleCounts() (int, int) {
+	packages := make(map[string]bool)
+	modules := make(map[string]bool)
+	for filePath := range p.files {
+		if p.contextFiles[filePath] {
+			continue
+		}
+		packages[p.ImportPathFor(filePath)] = true
+		if module := p.ModuleFor(filePath); module != nil {
+			modules[module.Dir] = true
+		}
+	}
+	return len(packages), len(modules)
+}
+
+// ResolveImportDir maps an import path to a local directory when it belongs
+// to the importing module, a workspace module or a locally replaced module
+func (p *Parser) ResolveImportDir(fromDir, importPath string) (string, bool) {
+	_, dir, ok := p.resolveImportRoot(fromDir, importPath)
+	return dir, ok
+}
+
+// resolveImportRoot returns the local module path and package directory an
+// import resolves to from fromDir
+func (p *Parser) resolveImportRoot(fromDir, importPath string) (string, string, bool) {
+	absDir, err := filepath.Abs(fromDir)
+	if err != nil {
+		return "", "", false
+	}
+	module := p.moduleForDir(absDir)
+	if module == nil {
+		return "", "", false
+	}
+
+	roots := map[string]string{module.Path: module.Dir}
+	for path, dir := range module.Replace {
+		roots[path] = dir
+	}
+	if workspace := p.WorkspaceFor(module); workspace != nil {
+		for _, workspaceModule := range workspace.Modules {
+			roots[workspaceModule.Path] = workspaceModule.Dir
+		}
+		for path, dir := range workspace.Replace {
+			roots[path] = dir
+		}
+	}
+
+	// The longest matching module path wins, as in the go command
+	bestPath, bestDir := "", ""
+	for path, dir := range roots {
+		if (importPath == path || strings.HasPrefix(importPath, path+"/")) && len(path) > len(bestPath) {
+			bestPath, bestDir = path, dir
+		}
+	}
+	if bestPath == "" {
+		return "", "", false
+	}
+	return bestPath, filepath.Join(bestDir, filepath.FromSlash(strings.TrimPrefix(importPath, bestPath))), true
+}
+
+// ClassifyImport reports whether an import from filePath is standard library,
+// from the same module, from another local module, or external
+func (p *Parser) ClassifyImport(filePath, importPath string) string {
+	if module := p.ModuleFor(filePath); module != nil {
+		if modulePath, _, ok := p.resolveImportRoot(filepath.Dir(filePath), importPath); ok {
+			if modulePath == module.Path {
+				return ImportModule
+			}
+			return ImportLocal
+		}
+	}
+
+	// Standard library paths have no dot in their first element
+	first := strings.SplitN(importPath, "/", 2)[0]
+	if !strings.Contains(first, ".") {
+		return ImportStd
+	}
+	return ImportExternal
+}
+
+// uses reports whether the workspace lists module
+func (w *Workspace) uses(module *Module) bool {
+	for _, used := range w.Modules {
+		if used.Dir == module.Dir {
+			return true
+		}
+	}
+	return false
+}
+
+// parseGoMod reads the module path, go version and local replacements of a go.mod
+func parseGoMod(dir, data string) *Module {
+	module := &Module{Dir: dir, Replace: make(map[string]string)}
+	for _, directive := range modDirectives(data) {
+		switch directive[0] {
+		case "module":
+			if len(directive) > 1 {
+				module.Path = unquoteModPath(directive[1])
+			}
+		case "go":
+			if len(directive) > 1 {
+				module.GoVersion = directive[1]
+			}
+		case "replace":
+			if path, target, ok := localReplacement(dir, directive[1:]); ok {
+				module.Replace[path] = target
+			}
+		}
+	}
+	return module
+}
+
+// parseGoWork reads the use and replace directives of a go.work file
+func (p *Parser) parseGoWork(dir, data string) *Workspace {
+	workspace := &Workspace{Dir: dir, Replace: make(map[string]string)}
+	for _, directive := range modDirectives(data) {
+		switch directive[0] {
+		case "use":
+			if len(directive) < 2 {
+				continue
+			}
+			moduleDir := filepath.Join(dir, filepath.FromSlash(unquoteModPath(directive[1])))
+			if module := p.moduleForDir(moduleDir); module != nil && module.Dir == moduleDir {
+				workspace.Modules = append(workspace.Modules, module)
+			}
+		case "replace":
+			if path, target, ok := localReplacement(dir, directive[1:]); ok {
+				workspace.Replace[path] = target
+			}
+		}
+	}
+	return workspace
+}
+
+// localReplacement parses "old [version] => new [version]" when new is a directory
+func localReplacement(dir string, fields []string) (string, string, bool) {
+	for i, field := range fields {
+		if field != "=>" || i == 0 || i+1 >= len(fields) {
+			continue
+		}
+		target := unquoteModPath(fields[i+1])
+		if !strings.HasPrefix(target, "./") && !strings.HasPrefix(target, "../") && !filepath.IsAbs(target) {
+			return "", "", false
+		}
+		if !filepath.IsAbs(target) {
+			target = filepath.Join(dir, filepath.FromSlash(target))
+		}
+		return unquoteModPath(fields[0]), target, true
+	}
+	return "", "", false
+}
+
+// modDirectives splits go.mod/go.work content into directives, expanding
+// parenthesized blocks so each entry carries its verb
+func modDirectives(data string) [][]string {
+	var directives [][]string
+	block := ""
+	for _, line := range strings.Split(data, "\n") {
+		if idx := strings.Index(line, "//"); idx >= 0 {
+			line = line[:idx]
+		}
+		fields := strings.Fields(line)
+		if len(fields) == 0 {
+			continue
+		}
+
+		switch {
+		case block != "" && fields[0] == ")":
+			block = ""
+		case block != "":
+			directives = append(directives, append([]string{block}, fields...))
+		case len(fields) == 2 && fields[1] == "(":
+			block = fields[0]
+		default:
+			directives = append(directives, fields)
+		}
+	}
+	return directives
+}
+
+// unquoteModPath strips optional quotes from a go.mod path token
+func unquoteModPath(value string) string {
+	if unquoted, err := strconv.Unquote(value); err == nil {
+		return unquoted
+	}
+	return value
+}
@@ -0,0 +1,137 @@
+package analyzer
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+// workspaceDir returns the absolute path of the testdata workspace, joined
+// with a slash-separated relative path
+func workspaceDir(t *testing.T, path string) string {
+	t.Helper()
+	dir, err := filepath.Abs(filepath.Join("testdata", "workspace", filepath.FromSlash(path)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return dir
+}
+
+func TestResolveImportDir(t *testing.T) {
+	workFile := workspaceDir(t, "go.work")
+
+	tests := []struct {
+		name       string
+		gowork     string
+		from       string
+		importPath string
+		want       string // Relative to the workspace; "" when unresolved
+	}{
+		{"own module", "", "app", "example.com/app/internal/store", "app/internal/store"},
+		{"own module root", "", "app/internal", "example.com/app", "app"},
+		{"workspace use block", "", "app", "example.com/lib/sub", "lib/sub"},
+		{"module replace with a local path", "", "app", "example.com/vendored/pkg", "vendored/pkg"},
+		{"workspace replace", "", "app", "example.com/tools/gen", "tools/gen"},
+		{"replace with a module path", "", "app", "example.com/remote", ""},
+		{"path prefix of another module", "", "app", "example.com/library", ""},
+		{"standard library", "", "app", "fmt", ""},
+		{"module outside the workspace", "", "outside", "example.com/lib", ""},
+		{"GOWORK=off", "off", "app", "example.com/lib/sub", ""},
+		{"GOWORK=off keeps module replaces", "off", "app", "example.com/vendored/pkg", "vendored/pkg"},
+		{"GOWORK naming the file", workFile, "app", "example.com/tools/gen", "tools/gen"},
+		{"GOWORK naming a missing file", workspaceDir(t, "missing.work"), "app", "example.com/lib", ""},
+	}
+
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			t.Setenv("GOWORK", test.gowork)
+			parser := NewParser(AnalysisOptions{})
+
+			dir, ok := parser.ResolveImportDir(workspaceDir(t, test.from), test.importPath)
+			want := ""
+			if test.want != "" {
+				want = workspaceDir(t, test.want)
+			}
+			if dir != want || ok != (want != "") {
+				t.Errorf("ResolveImportDir(%s, %s) = %q, %v; want %q", test.from, test.importPath, dir, ok, want)
+			}
+		})
+	}
+}
+
+func TestWorkspaceFor(t *testing.T) {
+	t.Setenv("GOWORK", "")
+	parser := NewParser(AnalysisOptions{})
+
+	workspace := parser.WorkspaceFor(parser.ModuleFor(workspaceDir(t, "app/main.go")))
+	if workspace == nil {
+		t.Fatal("app is not in the workspace")
+	}
+	var modules []string
+	for _, module := range workspace.Modules {
+		modules = append(modules, module.Path)
+	}
+	if len(modules) != 2 || modules[0] != "example.com/app" || modules[1] != "example.com/lib" {
+		t.Errorf("workspace modules %q, want app and lib", modules)
+	}
+	if got := workspace.Replace["example.com/tools"]; got != workspaceDir(t, "tools") {
+		t.Errorf("workspace replace %q", got)
+	}
+
+	if workspace := parser.WorkspaceFor(parser.ModuleFor(workspaceDir(t, "outside/main.go"))); workspace != nil {
+		t.Errorf("a module not listed in go.work got workspace %+v", workspace)
+	}
+}
+
+func TestClassifyImport(t *testing.T) {
+	t.Setenv("GOWORK", "")
+	parser := NewParser(AnalysisOptions{})
+	filePath := workspaceDir(t, "app/main.go")
+
+	tests := []struct {
+		importPath string
+		want       string
+	}{
+		{"example.com/app/internal/store", ImportModule},
+		{"example.com/lib", ImportLocal},
+		{"example.com/vendored", ImportLocal},
+		{"example.com/remote", ImportExternal},
+		{"net/http", ImportStd},
+	}
+	for _, test := range tests {
+		if got := parser.ClassifyImport(filePath, test.importPath); got != test.want {
+			t.Errorf("ClassifyImport(%s) = %s, want %s", test.importPath, got, test.want)
+		}
+	}
+}
+
+func TestParseGoMod(t *testing.T) {
+	module := parseGoMod("/src/app", `// The application module
+module "example.com/app" // quoted
+
+go 1.21
+
+replace (
+	example.com/a => ./a
+	example.com/b v1.0.0 => ../b v1.0.0
+	example.com/c => example.com/c-fork v1.0.0
+	example.com/d => /abs/d
+)
+`)
+	if module.Path != "example.com/app" || module.GoVersion != "1.21" {
+		t.Errorf("module %q go %q", module.Path, module.GoVersion)
+	}
+	want := map[string]string{
+		"example.com/a": filepath.Join("/src/app", "a"),
+		"example.com/b": filepath.Join("/src", "b"),
+		"example.com/d": "/abs/d",
+	}
+	if len(module.Replace) != len(want) {
+		t.Errorf("replacements %q, want %q", module.Replace, want)
+	}
+	for path, dir := range want {
+		if module.Replace[path] != dir {
+			t.Errorf("replace %s = %q, want %q", path, module.Replace[path], dir)
+		}
+	}
+}
@@ -0,0 +1,693 @@
+package analyzer
+
+import (
+	"fmt"
+	"go/ast"
+	"go/token"
+	"regexp"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// commonInitialisms are the initialisms Go spells in a single case
+var commonInitialisms = map[string]bool{
+	"ACL": true, "API": true, "ASCII": true, "CPU": true, "CSS": true, "DNS": true,
+	"EOF": true, "GUID": true, "HTML": true, "HTTP": true, "HTTPS": true, "ID": true,
+	"IP": true, "JSON": true, "QPS": true, "RAM": true, "RPC": true, "SLA": true,
+	"SMTP": true, "SQL": true, "SSH": true, "TCP": true, "TLS": true, "TTL": true,
+	"UDP": true, "UI": true, "UID": true, "UUID": true, "URI": true, "URL": true,
+	"VM": true, "XML": true, "XMPP": true, "XSRF": true, "XSS": true,
+}
+
+// generatedFilePattern matches the standard marker of generated Go files
+var generatedFilePattern = regexp.MustCompile(`^// Code generated .* DO NOT EDIT\.$`)
+
+// namedEntity is a declared name checked by the naming analyzer
+type namedEntity struct {
+	EntityInfo
+	Kind     string // function, method, struct, interface, type, const or var
+	Exported bool
+	TopLevel bool
+}
+
+// NamingAnalyzer checks declared names against Go naming idioms and the
+// configured naming conventions
+type NamingAnalyzer struct {
+	parser      *Parser
+	conventions []NamingConvention
+	patterns    []*regexp.Regexp // Compiled convention patterns, nil when invalid
+	errors      []Error
+}
+
+// NewNamingAnalyzer creates a new naming analyzer, compiling the convention
+// patterns once; invalid patterns are reported by Errors
+func NewNamingAnalyzer(parser *Parser, conventions []NamingConvention) *NamingAnalyzer {
+	n := &NamingAnalyzer{
+		parser:      parser,
+		conventions: conventions,
+		patterns:    make([]*regexp.Regexp, len(conventions)),
+	}
+	for index, convention := range conventions {
+		pattern, err := regexp.Compile(convention.Pattern)
+		if err != nil {
+			n.errors = append(n.errors, Error{
+				Message: fmt.Sprintf("Naming convention %q is not a valid regular expression: %v", convention.Pattern, err),
+				Type:    "naming",
+			})
+			continue
+		}
+		n.patterns[index] = pattern
+	}
+	return n
+}
+
+// Errors returns the configuration errors found while creating the analyzer
+func (n *NamingAnalyzer) Errors() []Error {
+	return n.errors
+}
+
+// Analyze performs naming analysis
+func (n *NamingAnalyzer) Analyze() []Violation {
+	var violations []Violation
+
+	entities := n.parser.namedEntities()
+	for _, entity := range entities {
+		violations = append(violations, n.analyzeMixedCaps(entity)...)
+		violations = append(violations, n.analyzeInitialisms(entity)...)
+		violations = append(violations, n.analyzeStutter(entity)...)
+		violations = append(violations, n.analyzeConventions(entity)...)
+	}
+	violations = append(violations, n.analyzeGetters()...)
+	violations = append(violations, n.analyzeReceiverNames()...)
+	violations = append(violations, n.analyzeInterfaceNames()...)
+
+	return violations
+}
+
+// analyzeMixedCaps reports names written with underscores instead of MixedCaps
+func (n *NamingAnalyzer) analyzeMixedCaps(entity namedEntity) []Violation {
+	var violations []Violation
+	if !strings.Contains(strings.Trim(entity.Name, "_"), "_") || isTestFunctionName(entity) {
+		return violations
+	}
+
+	suggested := mixedCapsName(entity.Name, entity.Exported)
+	violations = append(violations, n.parser.withEntityRange(Violation{
+		File:     entity.File,
+		Severity: "warning",
+		Message:  fmt.Sprintf("%s name %s uses underscores; Go names use MixedCaps", entity.Kind, entity.Name),
+		Details: map[string]interface{}{
+			"name":      entity.Name,
+			"entity":    entity.Kind,
+			"suggested": suggested,
+		},
+		Suggestion: fmt.Sprintf("Rename %s to %s", entity.Name, suggested),
+		Analyzer:   "naming",
+		Category:   "mixed-caps",
+	}, entity.EntityInfo))
+
+	return violations
+}
+
+// analyzeInitialisms reports initialisms such as Id, Url or Http written in mixed case
+func (n *NamingAnalyzer) analyzeInitialisms(entity namedEntity) []Violation {
+	var violations []Violation
+	if strings.Contains(entity.Name, "_") {
+		return violations
+	}
+
+	words := splitNameWords(entity.Name)
+	changed := false
+	for index, word := range words {
+		stem := strings.TrimRightFunc(word, unicode.IsDigit)
+		upper := strings.ToUpper(stem)
+		if !commonInitialisms[upper] || stem == upper {
+			continue
+		}
+		if index == 0 && stem == strings.ToLower(stem) {
+			// A leading all-lowercase initialism is correct in unexported names
+			continue
+		}
+		words[index] = upper + word[len(stem):]
+		changed = true
+	}
+	if !changed {
+		return violations
+	}
+
+	suggested := strings.Join(words, "")
+	violations = append(violations, n.parser.withEntityRange(Violation{
+		File:     entity.File,
+		Severity: "suggestion",
+		Message:  fmt.Sprintf("%s name %s should spell initialisms in a consistent case: %s", entity.Kind, entity.Name, suggested),
+		Details: map[string]interface{}{
+			"name":      entity.Name,
+			"entity":    entity.Kind,
+			"suggested": suggested,
+		},
+		Suggestion: fmt.Sprintf("Rename %s to %s", entity.Name, suggested),
+		Analyzer:   "naming",
+		Category:   "initialisms",
+	}, entity.EntityInfo))
+
+	return violations
+}
+
+// analyzeStutter reports exported names that repeat their package name, such as http.HTTPServer
+func (n *NamingAnalyzer) analyzeStutter(entity namedEntity) []Violation {
+	var violations []Violation
+	pkgName := entity.Package
+	if !entity.Exported || !entity.TopLevel || pkgName == "main" || strings.HasSuffix(pkgName, "_test") {
+		return violations
+	}
+	if len(entity.Name) <= len(pkgName) || !strings.EqualFold(entity.Name[:len(pkgName)], pkgName) {
+		return violations
+	}
+	rest := entity.Name[len(pkgName):]
+	if !unicode.IsUpper(rune(rest[0])) {
+		return violations
+	}
+
+	violations = append(violations, n.parser.withEntityRange(Violation{
+		File:     entity.File,
+		Severity: "suggestion",
+		Message:  fmt.Sprintf("%s.%s stutters; callers will write %s.%s", pkgName, entity.Name, pkgName, entity.Name),
+		Details: map[string]interface{}{
+			"name":      entity.Name,
+			"package":   pkgName,
+			"suggested": rest,
+		},
+		Suggestion: fmt.Sprintf("Rename %s to %s so it reads as %s.%s", entity.Name, rest, pkgName, rest),
+		Analyzer:   "naming",
+		Category:   "package-stutter",
+	}, entity.EntityInfo))
+
+	return violations
+}
+
+// analyzeConventions checks an entity against the configured naming conventions
+func (n *NamingAnalyzer) analyzeConventions(entity namedEntity) []Violation {
+	var violations []Violation
+
+	for index, convention := range n.conventions {
+		pattern := n.patterns[index]
+		if pattern == nil || !convention.appliesTo(n.parser, entity) || pattern.MatchString(entity.Name) {
+			continue
+		}
+
+		message := convention.Message
+		if message == "" {
+			message = fmt.Sprintf("%s name %s does not match naming convention %q", entity.Kind, entity.Name, convention.Pattern)
+		}
+		severity := convention.Severity
+		if severity == "" {
+			severity = "warning"
+		}
+		violations = append(violations, n.parser.withEntityRange(Violation{
+			File:     entity.File,
+			Severity: severity,
+			Message:  message,
+			Details: map[string]interface{}{
+				"name":    entity.Name,
+				"entity":  entity.Kind,
+				"pattern": convention.Pattern,
+			},
+			Analyzer: "naming",
+			Category: "naming-convention",
+		}, entity.EntityInfo))
+	}
+
+	return violations
+}
+
+// appliesTo reports whether a convention selects the entity
+func (c NamingConvention) appliesTo(parser *Parser, entity namedEntity) bool {
+	if c.Entity != "" && c.Entity != entity.Kind {
+		return false
+	}
+	if c.Visibility == "exported" && !entity.Exported || c.Visibility == "unexported" && entity.Exported {
+		return false
+	}
+	return c.Path == "" || parser.matchesFileOrPackage(c.Path, entity.File)
+}
+
+// analyzeGetters reports GetX methods that take no arguments; Go getters are named X
+func (n *NamingAnalyzer) analyzeGetters() []Violation {
+	var violations []Violation
+
+	fieldNames := make(map[string]map[string]bool)
+	for _, structInfo := range n.parser.ExtractStructs() {
+		fields := make(map[string]bool)
+		for _, field := range structInfo.Fields {
+			fields[field.Name] = true
+		}
+		fieldNames[structInfo.ImportPath+"."+structInfo.Name] = fields
+	}
+
+	for _, function := range n.parser.ExtractFunctions() {
+		if !function.IsMethod || n.parser.isGeneratedFile(function.File) {
+			continue
+		}
+		name := function.Name
+		if len(name) <= 3 || !strings.HasPrefix(name, "Get") || !unicode.IsUpper(rune(name[3])) {
+			continue
+		}
+		if len(function.Parameters) > 0 || function.ReturnType == "" {
+			continue
+		}
+		getter := name[3:]
+		if fieldNames[function.ImportPath+"."+receiverBaseName(function.Receiver)][getter] {
+			// The idiomatic name is taken by a field of the same name
+			continue
+		}
+
+		violations = append(violations, n.parser.withEntityRange(Violation{
+			File:     function.File,
+			Severity: "suggestion",
+			Message:  fmt.Sprintf("Getter %s should be named %s; Go getters do not use a Get prefix", name, getter),
+			Details: map[string]interface{}{
+				"method":    name,
+				"receiver":  function.Receiver,
+				"suggested": getter,
+			},
+			Suggestion: fmt.Sprintf("Rename %s to %s", name, getter),
+			Analyzer:   "naming",
+			Category:   "getter-prefix",
+		}, function.EntityInfo))
+	}
+
+	return violations
+}
+
+// analyzeReceiverNames reports methods whose receiver name differs from the
+// one used by most methods of the same type, and receivers named this or self
+func (n *NamingAnalyzer) analyzeReceiverNames() []Violation {
+	var violations []Violation
+
+	methodsByType := make(map[string][]Function)
+	for _, function := range n.parser.ExtractFunctions() {
+		if !function.IsMethod || function.ReceiverName == "" || function.ReceiverName == "_" {
+			continue
+		}
+		key := function.ImportPath + "." + receiverBaseName(function.Receiver)
+		methodsByType[key] = append(methodsByType[key], function)
+	}
+
+	for _, methods := range methodsByType {
+		counts := make(map[string]int)
+		for _, method := range methods {
+			counts[method.ReceiverName]++
+		}
+		preferred := ""
+		for _, name := range sortedCountKeys(counts) {
+			if name != "this" && name != "self" && counts[name] > counts[preferred] {
+				preferred = name
+			}
+		}
+		typeName := receiverBaseName(methods[0].Receiver)
+		if preferred == "" && typeName != "" {
+			preferred = strings.ToLower(typeName[:1])
+		}
+
+		for _, method := range methods {
+			name := method.ReceiverName
+			if name == preferred {
+				continue
+			}
+			message := fmt.Sprintf("Receiver name %s of %s.%s is inconsistent with %s used by other methods", name, typeName, method.Name, preferred)
+			if name == "this" || name == "self" {
+				message = fmt.Sprintf("Receiver of %s.%s is named %s; Go receivers use a short name reflecting the type", typeName, method.Name, name)
+			}
+
+			violations = append(violations, n.parser.withEntityRange(Violation{
+				File:     method.File,
+				Severity: "suggestion",
+				Message:  message,
+				Details: map[string]interface{}{
+					"type":      typeName,
+					"method":    method.Name,
+					"receiver":  name,
+					"suggested": preferred,
+				},
+				Fixes:      n.receiverRenameFixes(method, preferred),
+				Suggestion: fmt.Sprintf("Name the receiver %s consistently across the methods of %s", preferred, typeName),
+				Analyzer:   "naming",
+				Category:   "receiver-name",
+			}, method.EntityInfo))
+		}
+	}
+
+	return violations
+}
+
+// receiverRenameFixes renames a method's receiver and its uses in the body,
+// unless the new name is already used in the method
+func (n *NamingAnalyzer) receiverRenameFixes(method Function, name string) []Fix {
+	funcDecl := n.parser.funcDeclAt(method.File, method.namePos)
+	if funcDecl == nil || funcDecl.Recv == nil || len(funcDecl.Recv.List[0].Names) == 0 {
+		return nil
+	}
+	receiver := funcDecl.Recv.List[0].Names[0]
+
+	// Uses are resolved with type information when there is some, and
+	// otherwise with the parser's object resolution
+	var refersToReceiver func(ident *ast.Ident) bool
+	if typeInfo := n.parser.TypeInfo(method.File); typeInfo != nil {
+		if obj := typeInfo.Info.Defs[receiver]; obj != nil {
+			refersToReceiver = func(ident *ast.Ident) bool {
+				return typeInfo.Info.Uses[ident] == obj
+			}
+		}
+	}
+	if refersToReceiver == nil && receiver.Obj != nil {
+		refersToReceiver = func(ident *ast.Ident) bool {
+			return ident.Obj == receiver.Obj
+		}
+	}
+	if refersToReceiver == nil {
+		return nil
+	}
+
+	edits := []TextEdit{n.parser.textEdit(receiver.Pos(), receiver.End(), name)}
+	collides := false
+	ast.Inspect(funcDecl, func(node ast.Node) bool {
+		ident, ok := node.(*ast.Ident)
+		switch {
+		case !ok || ident == receiver:
+		case refersToReceiver(ident):
+			edits = append(edits, n.parser.textEdit(ident.Pos(), ident.End(), name))
+		case ident.Name == name:
+			collides = true
+		}
+		return !collides
+	})
+	if collides {
+		return nil
+	}
+
+	return []Fix{{
+		Description: fmt.Sprintf("Rename receiver %s to %s", receiver.Name, name),
+		Edits:       edits,
+	}}
+}
+
+// funcDeclAt returns the function declaration whose name starts at namePos
+func (p *Parser) funcDeclAt(filePath string, namePos token.Pos) *ast.FuncDecl {
+	file, ok := p.files[filePath]
+	if !ok {
+		return nil
+	}
+	for _, decl := range file.Decls {
+		if funcDecl, ok := decl.(*ast.FuncDecl); ok && funcDecl.Name.Pos() == namePos {
+			return funcDecl
+		}
+	}
+	return nil
+}
+
+// analyzeInterfaceNames reports single-method interfaces not named after
+// their method with an -er suffix
+func (n *NamingAnalyzer) analyzeInterfaceNames() []Violation {
+	var violations []Violation
+
+	for _, interfaceInfo := range n.parser.ExtractInterfaces() {
+		if len(interfaceInfo.Methods) != 1 || len(interfaceInfo.Embeds) > 0 || n.parser.isGeneratedFile(interfaceInfo.File) {
+			continue
+		}
+		suggested := agentNoun(interfaceInfo.Methods[0].Name)
+		if strings.HasSuffix(strings.ToLower(interfaceInfo.Name), "er") || strings.EqualFold(interfaceInfo.Name, suggested) {
+			continue
+		}
+
+		if !interfaceInfo.IsExported {
+			suggested = strings.ToLower(suggested[:1]) + suggested[1:]
+		}
+		violations = append(violations, n.parser.withEntityRange(Violation{
+			File:     interfaceInfo.File,
+			Severity: "suggestion",
+			Message:  fmt.Sprintf("Single-method interface %s should be named after its method, such as %s", interfaceInfo.Name, suggested),
+			Details: map[string]interface{}{
+				"interface": interfaceInfo.Name,
+				"method":    interfaceInfo.Methods[0].Name,
+				"suggested": suggested,
+			},
+			Suggestion: fmt.Sprintf("Rename %s to %s", interfaceInfo.Name, suggested),
+			Analyzer:   "naming",
+			Category:   "interface-naming",
+		}, interfaceInfo.EntityInfo))
+	}
+
+	return violations
+}
+
+// analyzeNaming reports exported top-level names in files or packages
+// matching rule.Path that do not match the rule.Exports regular expression
+func (r *RuleAnalyzer) analyzeNaming(rule Rule) []Violation {
+	var violations []Violation
+	if rule.Path == "" {
+		r.ruleError(rule, "naming rules require a path")
+		return violations
+	}
+	pattern, err := regexp.Compile(rule.Exports)
+	if err != nil {
+		r.ruleError(rule, fmt.Sprintf("exports %q is not a valid regular expression: %v", rule.Exports, err))
+		return violations
+	}
+
+	for _, entity := range r.parser.namedEntities() {
+		if !entity.Exported || !entity.TopLevel || pattern.MatchString(entity.Name) {
+			continue
+		}
+		if !r.parser.matchesFileOrPackage(rule.Path, entity.File) {
+			continue
+		}
+		violations = append(violations, r.parser.withEntityRange(r.ruleViolation(rule, entity.File,
+			fmt.Sprintf("Exported symbol %q does not match naming convention %q", entity.Name, rule.Exports),
+			map[string]interface{}{
+				"symbol": entity.Name,
+				"entity": entity.Kind,
+			}), entity.EntityInfo))
+	}
+
+	return violations
+}
+
+// namedEntities collects the declared functions, methods, types and
+// top-level constants and variables of non-generated files
+func (p *Parser) namedEntities() []namedEntity {
+	var entities []namedEntity
+
+	for _, function := range p.ExtractFunctions() {
+		kind := "function"
+		if function.IsMethod {
+			kind = "method"
+		}
+		entities = append(entities, namedEntity{
+			EntityInfo: function.EntityInfo,
+			Kind:       kind,
+			Exported:   function.IsExported,
+			TopLevel:   !function.IsMethod,
+		})
+	}
+	for _, structInfo := range p.ExtractStructs() {
+		entities = append(entities, namedEntity{
+			EntityInfo: structInfo.EntityInfo,
+			Kind:       "struct",
+			Exported:   structInfo.IsExported,
+			TopLevel:   true,
+		})
+	}
+	for _, interfaceInfo := range p.ExtractInterfaces() {
+		entities = append(entities, namedEntity{
+			EntityInfo: interfaceInfo.EntityInfo,
+			Kind:       "interface",
+			Exported:   interfaceInfo.IsExported,
+			TopLevel:   true,
+		})
+	}
+
+	// Other type declarations and package-level values
+	for filePath, file := range p.files {
+		for _, decl := range file.Decls {
+			genDecl, ok := decl.(*ast.GenDecl)
+			if !ok {
+				continue
+			}
+			for _, spec := range genDecl.Specs {
+				switch s := spec.(type) {
+				case *ast.TypeSpec:
+					switch s.Type.(type) {
+					case *ast.StructType, *ast.InterfaceType:
+						continue
+					}
+					entities = append(entities, p.valueEntity(s.Name, "type", filePath, file))
+				case *ast.ValueSpec:
+					kind := "var"
+					if genDecl.Tok == token.CONST {
+						kind = "const"
+					}
+					for _, name := range s.Names {
+						if name.Name != "_" {
+							entities = append(entities, p.valueEntity(name, kind, filePath, file))
+						}
+					}
+				}
+			}
+		}
+	}
+
+	var filtered []namedEntity
+	for _, entity := range entities {
+		if !p.isGeneratedFile(entity.File) {
+			filtered = append(filtered, entity)
+		}
+	}
+	return filtered
+}
+
+// valueEntity describes a declared identifier that has no extractor of its own
+func (p *Parser) valueEntity(name *ast.Ident, kind, filePath string, file *ast.File) namedEntity {
+	line := p.fileSet.Position(name.Pos()).Line
+	return namedEntity{
+		EntityInfo: EntityInfo{
+			Name:       name.Name,
+			Type:       kind,
+			File:       filePath,
+			StartLine:  line,
+			EndLine:    line,
+			Package:    file.Name.Name,
+			ImportPath: p.ImportPathFor(filePath),
+			Module:     p.modulePathFor(filePath),
+			namePos:    name.Pos(),
+			nameEnd:    name.End(),
+		},
+		Kind:     kind,
+		Exported: name.IsExported(),
+		TopLevel: true,
+	}
+}
+
+// isGeneratedFile reports whether a parsed file carries the generated code marker
+func (p *Parser) isGeneratedFile(filePath string) bool {
+	file, ok := p.files[filePath]
+	if !ok {
+		return false
+	}
+	for _, group := range file.Comments {
+		if group.Pos() > file.Package {
+			break
+		}
+		for _, comment := range group.List {
+			if generatedFilePattern.MatchString(comment.Text) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// isTestFunctionName reports whether an entity is a test, benchmark, example
+// or fuzz function, whose names may contain underscores
+func isTestFunctionName(entity namedEntity) bool {
+	if entity.Kind != "function" || !strings.HasSuffix(entity.File, "_test.go") {
+		return false
+	}
+	for _, prefix := range []string{"Test", "Benchmark", "Example", "Fuzz"} {
+		if strings.HasPrefix(entity.Name, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// splitNameWords splits a MixedCaps name into words, keeping runs of
+// capitals together: XMLHttpRequest -> XML, Http, Request
+func splitNameWords(name string) []string {
+	runes := []rune(name)
+	var words []string
+	start := 0
+	for i := 1; i < len(runes); i++ {
+		previous, current := runes[i-1], runes[i]
+		lowerToUpper := (unicode.IsLower(previous) || unicode.IsDigit(previous)) && unicode.IsUpper(current)
+		acronymEnd := unicode.IsUpper(previous) && unicode.IsUpper(current) && i+1 < len(runes) && unicode.IsLower(runes[i+1])
+		if lowerToUpper || acronymEnd {
+			words = append(words, string(runes[start:i]))
+			start = i
+		}
+	}
+	return append(words, string(runes[start:]))
+}
+
+// mixedCapsName rewrites an underscored name in MixedCaps, keeping its visibility
+func mixedCapsName(name string, exported bool) string {
+	var builder strings.Builder
+	for _, part := range strings.Split(name, "_") {
+		if part == "" {
+			continue
+		}
+		upper := strings.ToUpper(part)
+		if part == upper {
+			// SCREAMING_CASE words become Capitalized unless they are initialisms
+			part = part[:1] + strings.ToLower(part[1:])
+		}
+		switch {
+		case builder.Len() == 0 && !exported:
+			if commonInitialisms[upper] {
+				part = strings.ToLower(part)
+			} else {
+				part = strings.ToLower(part[:1]) + part[1:]
+			}
+		case commonInitialisms[upper]:
+			part = upper
+		default:
+			part = strings.ToUpper(part[:1]) + part[1:]
+		}
+		builder.WriteString(part)
+	}
+	return builder.String()
+}
+
+// agentNoun derives the conventional -er interface name from a method name,
+// doubling the final consonant of one-syllable verbs (Scan -> Scanner) and
+// using -ator for verbs in -ate (Validate -> Validator, but Update -> Updater)
+func agentNoun(method string) string {
+	if strings.HasSuffix(method, "ate") && !strings.HasSuffix(method, "pdate") {
+		return strings.TrimSuffix(method, "e") + "or"
+	}
+	if strings.HasSuffix(method, "e") {
+		return method + "r"
+	}
+
+	isVowel := func(r byte) bool { return strings.IndexByte("aeiouAEIOU", r) >= 0 }
+	vowelGroups := 0
+	for i := 0; i < len(method); i++ {
+		if isVowel(method[i]) && (i == 0 || !isVowel(method[i-1])) {
+			vowelGroups++
+		}
+	}
+	if n := len(method); vowelGroups == 1 && n >= 3 && !isVowel(method[n-3]) && isVowel(method[n-2]) &&
+		!isVowel(method[n-1]) && strings.IndexByte("wxy", method[n-1]) < 0 {
+		return method + method[n-1:] + "er"
+	}
+	return method + "er"
+}
+
+// receiverBaseName strips pointers and type parameters from a receiver type
+func receiverBaseName(receiver string) string {
+	name := strings.TrimLeft(receiver, "*")
+	if index := strings.Index(name, "["); index >= 0 {
+		name = name[:index]
+	}
+	return name
+}
+
+// sortedCountKeys returns the keys of a count map in ascending order
+func sortedCountKeys(counts map[string]int) []string {
+	keys := make([]string, 0, len(counts))
+	for key := range counts {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,56 @@
+package analyzer
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestAgentNoun(t *testing.T) {
+	tests := map[string]string{
+		"Read":       "Reader",
+		"Close":      "Closer",
+		"Scan":       "Scanner",
+		"Flush":      "Flusher",
+		"Validate":   "Validator",
+		"Generate":   "Generator",
+		"Update":     "Updater",
+		"BulkUpdate": "BulkUpdater",
+	}
+	for method, want := range tests {
+		if got := agentNoun(method); got != want {
+			t.Errorf("agentNoun(%s) = %s, want %s", method, got, want)
+		}
+	}
+}
+
+func TestInterfaceNames(t *testing.T) {
+	result := analyzeSource(t, `package sample
+
+type ValidationStrategy interface {
+	Validate(input string) error
+}
+
+type Validator interface {
+	Validate(input string) error
+}
+
+type Reader interface {
+	Read(p []byte) (int, error)
+}
+
+type storage interface {
+	Update(key string) error
+}
+`, "naming")
+
+	var suggested []string
+	for _, violation := range result.Violations {
+		if violation.Category == "interface-naming" {
+			suggested = append(suggested, violation.Details["interface"].(string)+" -> "+violation.Details["suggested"].(string))
+		}
+	}
+	want := []string{"ValidationStrategy -> Validator", "storage -> updater"}
+	if !reflect.DeepEqual(suggested, want) {
+		t.Errorf("got %q, want %q", suggested, want)
+	}
+}
@@ -0,0 +1,102 @@
+package analyzer
+
+import (
+	"go/build"
+	"go/parser"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// overlayContent returns the in-memory content for a path, if one was supplied
+func (p *Parser) overlayContent(filePath string) ([]byte, bool) {
+	content, ok := p.overlay[absolutePath(filePath)]
+	if !ok {
+		return nil, false
+	}
+	return []byte(content), true
+}
+
+// LoadPackageContext parses the on-disk siblings of filePath that belong to
+// the same package so package-level analysis sees the whole package. Sibling
+// files are marked as context and are not reported on.
+func (p *Parser) LoadPackageContext(filePath string) error {
+	target, ok := p.files[filePath]
+	if !ok {
+		return nil
+	}
+
+	dir := filepath.Dir(filePath)
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil // Purely in-memory file, nothing to layer over
+		}
+		return err
+	}
+
+	includeTests := strings.HasSuffix(filePath, "_test.go")
+	var siblings []string
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() || !strings.HasSuffix(name, ".go") {
+			continue
+		}
+		if strings.HasSuffix(name, "_test.go") && !includeTests {
+			continue
+		}
+		siblings = append(siblings, filepath.Join(dir, name))
+	}
+	sort.Strings(siblings)
+
+	// Parsed files may be named relative to another directory or uncleaned,
+	// as overlay keys often are, so compare absolute paths
+	parsed := make(map[string]bool, len(p.files))
+	for parsedPath := range p.files {
+		parsed[absolutePath(parsedPath)] = true
+	}
+
+	for _, sibling := range siblings {
+		if parsed[absolutePath(sibling)] {
+			continue
+		}
+		if match, err := build.Default.MatchFile(dir, filepath.Base(sibling)); err != nil || !match {
+			continue
+		}
+
+		src, ok := p.overlayContent(sibling)
+		if !ok {
+			if src, err = os.ReadFile(sibling); err != nil {
+				continue
+			}
+		}
+
+		// Broken siblings must not fail analysis of the edited file
+		file, err := parser.ParseFile(p.fileSet, sibling, src, parser.ParseComments)
+		if err != nil || file.Name.Name != target.Name.Name {
+			continue
+		}
+
+		p.files[sibling] = file
+		p.sources[sibling] = src
+		p.contextFiles[sibling] = true
+	}
+
+	p.invalidate()
+	return nil
+}
+
+// IsContextFile reports whether a file was loaded only as package context
+func (p *Parser) IsContextFile(filePath string) bool {
+	return p.contextFiles[filePath]
+}
+
+// absolutePath returns an absolute, cleaned path, or the path itself when the
+// working directory is unknown
+func absolutePath(path string) string {
+	if abs, err := filepath.Abs(path); err == nil {
+		return abs
+	}
+	return filepath.Clean(path)
+}
@@ -0,0 +1,114 @@
+package analyzer
+
+import (
+	"go/ast"
+	"sort"
+	"strings"
+)
+
+// Packages groups the parsed files into packages with their imports,
+// exported names and declared entities, sorted by import path
+func (p *Parser) Packages() []Package {
+	functions := p.ExtractFunctions()
+	structs := p.ExtractStructs()
+	interfaces := p.ExtractInterfaces()
+
+	var packages []Package
+	for key, paths := range p.packageFiles() {
+		pkg := Package{
+			Name:  key.Name,
+			Path:  p.packagePath(paths[0]),
+			Files: paths,
+		}
+
+		inPackage := make(map[string]bool)
+		imports := make(map[string]bool)
+		exports := make(map[string]bool)
+		for _, filePath := range paths {
+			inPackage[filePath] = true
+			file := p.files[filePath]
+			if !strings.HasSuffix(filePath, "_test.go") {
+				for _, importSpec := range file.Imports {
+					imports[importPathOf(importSpec)] = true
+				}
+			}
+			for name := range exportedNames(file) {
+				exports[name] = true
+			}
+		}
+		pkg.Imports = sortedKeys(imports)
+		pkg.Exports = sortedKeys(exports)
+
+		for _, function := range functions {
+			if inPackage[function.File] {
+				pkg.Functions = append(pkg.Functions, function)
+			}
+		}
+		for _, structInfo := range structs {
+			if inPackage[structInfo.File] {
+				pkg.Structs = append(pkg.Structs, structInfo)
+			}
+		}
+		for _, interfaceInfo := range interfaces {
+			if inPackage[interfaceInfo.File] {
+				pkg.Interfaces = append(pkg.Interfaces, interfaceInfo)
+			}
+		}
+
+		packages = append(packages, pkg)
+	}
+
+	sort.Slice(packages, func(i, j int) bool {
+		return packages[i].Path < packages[j].Path
+	})
+	return packages
+}
+
+// packagePath returns the import path of a file's package, distinguishing
+// external test packages from the package under test
+func (p *Parser) packagePath(filePath string) string {
+	importPath := p.ImportPathFor(filePath)
+	if file, ok := p.files[filePath]; ok && strings.HasSuffix(file.Name.Name, "_test") {
+		return importPath + "_test"
+	}
+	return importPath
+}
+
+// exportedNames returns the exported top-level identifiers declared in a file
+func exportedNames(file *ast.File) map[string]bool {
+	names := make(map[string]bool)
+	for _, decl := range file.Decls {
+		switch node := decl.(type) {
+		case *ast.FuncDecl:
+			if node.Recv == nil && node.Name.IsExported() {
+				names[node.Name.Name] = true
+			}
+		case *ast.GenDecl:
+			for _, spec := range node.Specs {
+				switch s := spec.(type) {
+				case *ast.TypeSpec:
+					if s.Name.IsExported() {
+						names[s.Name.Name] = true
+					}
+				case *ast.ValueSpec:
+					for _, name := range s.Names {
+						if name.IsExported() {
+							names[name.Name] = true
+						}
+					}
+				}
+			}
+		}
+	}
+	return names
+}
+
+// sortedKeys returns the keys of a set in ascending order
+func sortedKeys(set map[string]bool) []string {
+	keys := make([]string, 0, len(set))
+	for key := range set {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -5,22 +5,45 @@ import (
 	"go/ast"
 	"go/parser"
 	"go/token"
+	"go/types"
+	"os"
 	"strings"
 )
 
 // Parser handles Go AST parsing and entity extraction
 type Parser struct {
-	fileSet *token.FileSet
-	files   map[string]*ast.File
-	options AnalysisOptions
+	fileSet      *token.FileSet
+	files        map[string]*ast.File
+	sources      map[string][]byte
+	options      AnalysisOptions
+	overlay      map[string]string // In-memory file contents keyed by absolute, cleaned path
+	contextFiles map[string]bool   // Files parsed only as package context
+	importer     *localImporter
+	modules      map[string]*Module    // Directory -> enclosing module
+	workspaces   map[string]*Workspace // Module directory -> workspace
+	typeInfo     map[packageKey]*PackageTypes
+	relations    map[packageKey]map[string]*typeRelation
+	hashes       map[string]string // File -> SHA-256 of its source, for fix edits
 }
 
 // NewParser creates a new Go parser
 func NewParser(options AnalysisOptions) *Parser {
+	overlay := make(map[string]string)
+	for path, content := range options.Overlay {
+		overlay[absolutePath(path)] = content
+	}
+
 	return &Parser{
-		fileSet: token.NewFileSet(),
-		files:   make(map[string]*ast.File),
-		options: options,
+		fileSet:      token.NewFileSet(),
+		files:        make(map[string]*ast.File),
+		sources:      make(map[string][]byte),
+		options:      options,
+		overlay:      overlay,
+		contextFiles: make(map[string]bool),
+		modules:      make(map[string]*Module),
+		workspaces:   make(map[string]*Workspace),
+		typeInfo:     make(map[packageKey]*PackageTypes),
+		hashes:       make(map[string]string),
 	}
 }
 
@@ -31,14 +54,24 @@ func (p *Parser) ParseFiles(filePaths []string) error {
 			continue
 		}
 
-		file, err := parser.ParseFile(p.fileSet, filePath, nil, parser.ParseComments)
+		src, ok := p.overlayContent(filePath)
+		if !ok {
+			var err error
+			if src, err = os.ReadFile(filePath); err != nil {
+				return fmt.Errorf("failed to read %s: %w", filePath, err)
+			}
+		}
+
+		file, err := parser.ParseFile(p.fileSet, filePath, src, parser.ParseComments)
 		if err != nil {
 			return fmt.Errorf("failed to parse %s: %w", filePath, err)
 		}
 
 		p.files[filePath] = file
+		p.sources[filePath] = src
 	}
 
+	p.invalidate()
 	return nil
 }
 
@@ -54,9 +87,20 @@ func (p *Parser) ParseContent(filePath, content string) error {
 	}
 
 	p.files[filePath] = file
+	p.sources[filePath] = []byte(content)
+	delete(p.contextFiles, filePath)
+	p.invalidate()
 	return nil
 }
 
+// invalidate drops cached per-package results after the file set changes
+func (p *Parser) invalidate() {
+	p.typeInfo = make(map[packageKey]*PackageTypes)
+	p.relations = nil
+	p.importer = nil
+	p.hashes = make(map[string]string)
+}
+
 // ExtractFunctions extracts all functions from parsed files
 func (p *Parser) ExtractFunctions() []Function {
 	var functions []Function
@@ -134,12 +178,16 @@ func (p *Parser) extractFunction(funcDecl *ast.FuncDecl, filePath string, file *
 
 	function := Function{
 		EntityInfo: EntityInfo{
-			Name:      funcDecl.Name.Name,
-			Type:      "function",
-			File:      filePath,
-			StartLine: pos.Line,
-			EndLine:   end.Line,
-			Package:   file.Name.Name,
+			Name:       funcDecl.Name.Name,
+			Type:       "function",
+			File:       filePath,
+			StartLine:  pos.Line,
+			EndLine:    end.Line,
+			Package:    file.Name.Name,
+			ImportPath: p.ImportPathFor(filePath),
+			Module:     p.modulePathFor(filePath),
+			namePos:    funcDecl.Name.Pos(),
+			nameEnd:    funcDecl.Name.End(),
 		},
 		IsMethod:   funcDecl.Recv != nil,
 		IsExported: ast.IsExported(funcDecl.Name.Name),
@@ -149,6 +197,9 @@ func (p *Parser) extractFunction(funcDecl *ast.FuncDecl, filePath string, file *
 	if funcDecl.Recv != nil && len(funcDecl.Recv.List) > 0 {
 		if field := funcDecl.Recv.List[0]; field.Type != nil {
 			function.Receiver = p.typeToString(field.Type)
+			if len(field.Names) > 0 {
+				function.ReceiverName = field.Names[0].Name
+			}
 		}
 	}
 
@@ -205,12 +256,16 @@ func (p *Parser) extractStruct(typeSpec *ast.TypeSpec, structType *ast.StructTyp
 
 	structInfo := Struct{
 		EntityInfo: EntityInfo{
-			Name:      typeSpec.Name.Name,
-			Type:      "struct",
-			File:      filePath,
-			StartLine: pos.Line,
-			EndLine:   end.Line,
-			Package:   file.Name.Name,
+			Name:       typeSpec.Name.Name,
+			Type:       "struct",
+			File:       filePath,
+			StartLine:  pos.Line,
+			EndLine:    end.Line,
+			Package:    file.Name.Name,
+			ImportPath: p.ImportPathFor(filePath),
+			Module:     p.modulePathFor(filePath),
+			namePos:    typeSpec.Name.Pos(),
+			nameEnd:    typeSpec.Name.End(),
 		},
 		IsExported: ast.IsExported(typeSpec.Name.Name),
 	}
@@ -245,6 +300,15 @@ func (p *Parser) extractStruct(typeSpec *ast.TypeSpec, structType *ast.StructTyp
 		}
 	}
 
+	// Attach method set and interface satisfaction
+	if relation := p.typeRelationFor(filePath, file, structInfo.Name); relation != nil {
+		structInfo.Methods = relation.Methods
+		structInfo.PromotedMethods = relation.PromotedMethods
+		structInfo.Embeds = relation.Embeds
+		structInfo.Implements = relation.Implements
+		structInfo.ImplementsSource = relation.Source
+	}
+
 	// Build signature
 	structInfo.Signature = fmt.Sprintf("type %s struct", structInfo.Name)
 
@@ -258,12 +322,16 @@ func (p *Parser) extractInterface(typeSpec *ast.TypeSpec, interfaceType *ast.Int
 
 	interfaceInfo := Interface{
 		EntityInfo: EntityInfo{
-			Name:      typeSpec.Name.Name,
-			Type:      "interface",
-			File:      filePath,
-			StartLine: pos.Line,
-			EndLine:   end.Line,
-			Package:   file.Name.Name,
+			Name:       typeSpec.Name.Name,
+			Type:       "interface",
+			File:       filePath,
+			StartLine:  pos.Line,
+			EndLine:    end.Line,
+			Package:    file.Name.Name,
+			ImportPath: p.ImportPathFor(filePath),
+			Module:     p.modulePathFor(filePath),
+			namePos:    typeSpec.Name.Pos(),
+			nameEnd:    typeSpec.Name.End(),
 		},
 		IsExported: ast.IsExported(typeSpec.Name.Name),
 	}
@@ -308,10 +376,17 @@ func (p *Parser) extractInterface(typeSpec *ast.TypeSpec, interfaceType *ast.Int
 						interfaceInfo.Methods = append(interfaceInfo.Methods, methodInfo)
 					}
 				}
+			} else {
+				// Embedded interface, possibly package-qualified (io.Reader)
+				interfaceInfo.Embeds = append(interfaceInfo.Embeds, types.ExprString(method.Type))
 			}
 		}
 	}
 
+	if relation := p.typeRelationFor(filePath, file, interfaceInfo.Name); relation != nil {
+		interfaceInfo.ImplementedBy = relation.ImplementedBy
+	}
+
 	// Build signature
 	interfaceInfo.Signature = fmt.Sprintf("type %s interface", interfaceInfo.Name)
 
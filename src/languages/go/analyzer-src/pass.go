@@ -0,0 +1,122 @@
+package analyzer
+
+import (
+	"fmt"
+	"go/types"
+	"reflect"
+)
+
+// Fact is information about a types.Object that a check exports for the
+// checks that require it. Facts are pointers to structs; each fact type is
+// stored once per object.
+type Fact interface {
+	AFact()
+}
+
+// factKey identifies one fact type of one object
+type factKey struct {
+	object   string
+	factType reflect.Type
+}
+
+// Pass is the view of one analysis a check runs with: the parsed files and
+// the facts exported by the checks it requires. Facts are keyed by
+// package-qualified names, so a fact exported for a function in one package
+// is visible to calls from every other analyzed package.
+type Pass struct {
+	Check    Check
+	Parser   *Parser
+	analyzer *Analyzer
+	facts    map[factKey]Fact
+	errors   []Error
+}
+
+// ReportError records an error, such as an invalid configured pattern, that
+// is returned with the results of the check
+func (p *Pass) ReportError(err Error) {
+	p.errors = append(p.errors, err)
+}
+
+// ExportObjectFact records a fact about obj
+func (p *Pass) ExportObjectFact(obj types.Object, fact Fact) {
+	if obj == nil {
+		return
+	}
+	p.facts[factKey{object: objectKey(obj), factType: reflect.TypeOf(fact)}] = fact
+}
+
+// ImportObjectFact copies the fact of fact's type recorded about obj into
+// fact, reporting whether there was one
+func (p *Pass) ImportObjectFact(obj types.Object, fact Fact) bool {
+	if obj == nil {
+		return false
+	}
+	stored, ok := p.facts[factKey{object: objectKey(obj), factType: reflect.TypeOf(fact)}]
+	if !ok {
+		return false
+	}
+	reflect.ValueOf(fact).Elem().Set(reflect.ValueOf(stored).Elem())
+	return true
+}
+
+// objectKey names an object the same way in every package that refers to
+// it, since each package is type-checked separately
+func objectKey(obj types.Object) string {
+	if fn, ok := obj.(*types.Func); ok {
+		return fn.Origin().FullName()
+	}
+	if obj.Pkg() == nil {
+		return obj.Name()
+	}
+	return obj.Pkg().Path() + "." + obj.Name()
+}
+
+// scheduleChecks orders checks after the checks they require, adding
+// required checks that were not enabled. Unknown requirements and cycles
+// are reported and the checks depending on them dropped.
+func scheduleChecks(checks []Check) ([]Check, []Error) {
+	var order []Check
+	var errors []Error
+	state := make(map[string]int) // 1 while visiting, 2 once scheduled, 3 when unusable
+
+	var visit func(check Check) bool
+	visit = func(check Check) bool {
+		switch state[check.Name()] {
+		case 1:
+			errors = append(errors, Error{
+				Message: fmt.Sprintf("Analyzer %q is part of a dependency cycle", check.Name()),
+				Type:    "analyzer",
+			})
+			return false
+		case 2:
+			return true
+		case 3:
+			return false
+		}
+
+		state[check.Name()] = 1
+		for _, name := range check.Requirements().Checks {
+			required, ok := LookupCheck(name)
+			if !ok {
+				errors = append(errors, Error{
+					Message: fmt.Sprintf("Analyzer %q requires unknown analyzer %q", check.Name(), name),
+					Type:    "analyzer",
+				})
+				state[check.Name()] = 3
+				return false
+			}
+			if !visit(required) {
+				state[check.Name()] = 3
+				return false
+			}
+		}
+		state[check.Name()] = 2
+		order = append(order, check)
+		return true
+	}
+
+	for _, check := range checks {
+		visit(check)
+	}
+	return order, errors
+}
@@ -0,0 +1,306 @@
+package analyzer
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"testing"
+)
+
+// registerTestCheck registers a check that reports nothing for the duration
+// of the test
+func registerTestCheck(t *testing.T, name string, requires ...string) Check {
+	t.Helper()
+	check := &checkFunc{
+		name:         name,
+		requirements: Requirements{Checks: requires},
+		run:          func(pass *Pass) []Violation { return nil },
+	}
+	RegisterCheck(check)
+	t.Cleanup(func() { delete(registry, name) })
+	return check
+}
+
+// checkNames returns the names of checks in order
+func checkNames(checks []Check) []string {
+	var names []string
+	for _, check := range checks {
+		names = append(names, check.Name())
+	}
+	return names
+}
+
+// errorMessages returns the messages of errors in order
+func errorMessages(errors []Error) []string {
+	var messages []string
+	for _, err := range errors {
+		messages = append(messages, err.Message)
+	}
+	return messages
+}
+
+func TestScheduleChecks(t *testing.T) {
+	base := registerTestCheck(t, "test-base")
+	middle := registerTestCheck(t, "test-middle", "test-base")
+	top := registerTestCheck(t, "test-top", "test-middle", "test-base")
+	orphan := registerTestCheck(t, "test-orphan", "test-missing")
+	cycleA := registerTestCheck(t, "test-cycle-a", "test-cycle-b")
+	registerTestCheck(t, "test-cycle-b", "test-cycle-a")
+	aboveCycle := registerTestCheck(t, "test-above-cycle", "test-cycle-a")
+
+	tests := []struct {
+		name   string
+		checks []Check
+		want   []string
+		errors []string
+	}{
+		{
+			name:   "required checks are added and run first",
+			checks: []Check{top},
+			want:   []string{"test-base", "test-middle", "test-top"},
+		},
+		{
+			name:   "enabled requirements run once",
+			checks: []Check{middle, base, top},
+			want:   []string{"test-base", "test-middle", "test-top"},
+		},
+		{
+			name:   "unknown requirement drops the check",
+			checks: []Check{orphan, base},
+			want:   []string{"test-base"},
+			errors: []string{`Analyzer "test-orphan" requires unknown analyzer "test-missing"`},
+		},
+		{
+			name:   "cycle drops the checks on it and above it",
+			checks: []Check{aboveCycle, base, cycleA},
+			want:   []string{"test-base"},
+			errors: []string{`Analyzer "test-cycle-a" is part of a dependency cycle`},
+		},
+	}
+
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			order, errors := scheduleChecks(test.checks)
+			if got := checkNames(order); !reflect.DeepEqual(got, test.want) {
+				t.Errorf("order %q, want %q", got, test.want)
+			}
+			if got := errorMessages(errors); !reflect.DeepEqual(got, test.errors) {
+				t.Errorf("errors %q, want %q", got, test.errors)
+			}
+		})
+	}
+}
+
+func TestFactPassesAreNotListedAsAnalyzers(t *testing.T) {
+	for _, name := range registeredCheckNames() {
+		if check, _ := LookupCheck(name); len(check.Categories()) == 0 {
+			t.Errorf("fact pass %q is listed as an available analyzer", name)
+		}
+	}
+}
+
+// writeModule writes files below dir and returns the Go files among them
+func writeModule(t *testing.T, dir string, files map[string]string) []string {
+	t.Helper()
+	var goFiles []string
+	for name, content := range files {
+		filePath := filepath.Join(dir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(filePath), 0755); err != nil {
+			t.Fatal(err)
+		}
+		writeFile(t, filePath, content)
+		if filepath.Ext(name) == ".go" {
+			goFiles = append(goFiles, filePath)
+		}
+	}
+	sort.Strings(goFiles)
+	return goFiles
+}
+
+func TestMayPanicAcrossPackages(t *testing.T) {
+	files := writeModule(t, t.TempDir(), map[string]string{
+		"go.mod": "module example.com/m\n\ngo 1.19\n",
+		"a/a.go": `package a
+
+import "example.com/m/b"
+
+type Service struct{}
+
+func (s *Service) Run(name string) string {
+	return b.Must(name)
+}
+
+func (s *Service) Safe(name string) string {
+	return b.Recovered(name)
+}
+`,
+		"b/b.go": `package b
+
+import "regexp"
+
+func Must(pattern string) string {
+	return regexp.MustCompile(pattern).String()
+}
+
+func Recovered(name string) (result string) {
+	defer func() {
+		if recover() != nil {
+			result = ""
+		}
+	}()
+	return Must(name)
+}
+`,
+	})
+
+	result, err := NewAnalyzer(AnalysisOptions{Analyzers: []string{"solid"}}).Analyze(files)
+	if err != nil {
+		t.Fatalf("analysis failed: %v", err)
+	}
+
+	var panicking []string
+	for _, violation := range result.Violations {
+		if violation.Category == "liskov-substitution" {
+			panicking = append(panicking, violation.Details["function"].(string)+" via "+violation.Details["panicsVia"].(string))
+		}
+	}
+	want := []string{"Run via example.com/m/b.Must"}
+	if !reflect.DeepEqual(panicking, want) {
+		t.Errorf("got %q, want %q", panicking, want)
+	}
+}
+
+func TestResourceLeaks(t *testing.T) {
+	result := analyzeSource(t, `package sample
+
+import (
+	"io"
+	"os"
+)
+
+func leaked(path string) {
+	f, err := os.Open(path)
+	if err != nil {
+		return
+	}
+	f.Stat()
+}
+
+func discarded(path string) {
+	_, _ = os.Create(path)
+}
+
+func deferred(path string) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	return nil
+}
+
+func returned(path string) (*os.File, error) {
+	f, err := os.Open(path)
+	return f, err
+}
+
+func stored(path string, files map[string]io.Closer) {
+	f, _ := os.Open(path)
+	files[path] = f
+}
+
+func closeQuietly(closer io.Closer) {
+	_ = closer.Close()
+}
+
+func release(first, second io.Closer) {
+	second.Close()
+}
+
+func handedOver(path string) {
+	f, _ := os.Open(path)
+	closeQuietly(f)
+}
+
+func handedToWrongParam(path string) {
+	f, _ := os.Open(path)
+	release(f, nil)
+}
+`, "resources")
+
+	var leaks []string
+	for _, violation := range result.Violations {
+		leaks = append(leaks, violation.Message)
+	}
+	want := []string{
+		"f opened by os.Open is never closed",
+		"The result of os.Create is discarded without being closed",
+		"f opened by os.Open is never closed",
+	}
+	if !reflect.DeepEqual(leaks, want) {
+		t.Errorf("got %q, want %q", leaks, want)
+	}
+	if len(result.Violations) == 3 && result.Violations[2].Line != 53 {
+		t.Errorf("leak reported on line %d, want 53", result.Violations[2].Line)
+	}
+}
+
+func TestExhaustiveSwitches(t *testing.T) {
+	result := analyzeSource(t, `package sample
+
+type Color int
+
+const (
+	Red Color = iota
+	Green
+	Blue
+)
+
+type Single int
+
+const Only Single = 1
+
+func name(c Color) string {
+	switch c {
+	case Red:
+		return "red"
+	case 1:
+		return "green"
+	}
+	return ""
+}
+
+func withDefault(c Color) string {
+	switch c {
+	case Red:
+		return "red"
+	default:
+		return "other"
+	}
+}
+
+func complete(c Color) bool {
+	switch c {
+	case Red, Green, Blue:
+		return true
+	}
+	return false
+}
+
+func notEnum(s Single) bool {
+	switch s {
+	}
+	return false
+}
+`, "exhaustive")
+
+	if len(result.Violations) != 1 {
+		t.Fatalf("got %d violations, want 1: %+v", len(result.Violations), result.Violations)
+	}
+	violation := result.Violations[0]
+	if violation.Message != "Switch on Color is missing cases Blue" || violation.Line != 16 {
+		t.Errorf("got %q on line %d, want the missing Blue case on line 16", violation.Message, violation.Line)
+	}
+}
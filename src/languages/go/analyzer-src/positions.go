@@ -0,0 +1,68 @@
+package analyzer
+
+import (
+	"go/token"
+	"unicode/utf8"
+)
+
+// Column encodings accepted in AnalysisOptions.ColumnEncoding
+const (
+	ColumnEncodingUTF8  = "utf-8"
+	ColumnEncodingUTF16 = "utf-16"
+)
+
+// position converts a token position into a line and 1-based column,
+// counting columns in the configured encoding
+func (p *Parser) position(pos token.Pos) (int, int) {
+	position := p.fileSet.Position(pos)
+	if p.options.ColumnEncoding != ColumnEncodingUTF16 {
+		return position.Line, position.Column
+	}
+
+	src := p.sources[position.Filename]
+	lineStart := position.Offset - (position.Column - 1)
+	if src == nil || lineStart < 0 || position.Offset > len(src) {
+		return position.Line, position.Column
+	}
+	return position.Line, utf16Length(src[lineStart:position.Offset]) + 1
+}
+
+// withRange sets the start and end positions and the source snippet of a
+// violation from a source span
+func (p *Parser) withRange(violation Violation, from, to token.Pos) Violation {
+	if !from.IsValid() {
+		return violation
+	}
+	violation.Line, violation.Column = p.position(from)
+	if to.IsValid() {
+		violation.EndLine, violation.EndColumn = p.position(to)
+	} else {
+		violation.EndLine, violation.EndColumn = violation.Line, violation.Column
+	}
+	violation.Snippet = p.renderSnippet(from, to)
+	return violation
+}
+
+// withEntityRange positions a violation on the name of a declared entity
+func (p *Parser) withEntityRange(violation Violation, entity EntityInfo) Violation {
+	if !entity.namePos.IsValid() {
+		violation.Line, violation.EndLine = entity.StartLine, entity.StartLine
+		return violation
+	}
+	return p.withRange(violation, entity.namePos, entity.nameEnd)
+}
+
+// utf16Length counts the UTF-16 code units needed to encode src
+func utf16Length(src []byte) int {
+	length := 0
+	for len(src) > 0 {
+		r, size := utf8.DecodeRune(src)
+		src = src[size:]
+		if r >= 0x10000 {
+			length += 2
+		} else {
+			length++
+		}
+	}
+	return length
+}
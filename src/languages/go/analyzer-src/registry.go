@@ -0,0 +1,198 @@
+package analyzer
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Check is an analysis the Analyzer runs when its name is listed in
+// AnalysisOptions.Analyzers
+type Check interface {
+	Name() string
+	Description() string
+	Categories() []string
+	Requirements() Requirements
+	Run(pass *Pass) []Violation
+}
+
+// Requirements lists what a check needs beyond the parsed syntax
+type Requirements struct {
+	TypeInfo bool     `json:"typeInfo"`         // Uses go/types information, falling back to syntax when type checking is skipped
+	Checks   []string `json:"checks,omitempty"` // Checks that must run first, such as the fact passes whose facts it imports
+}
+
+// checkFunc is a Check backed by a run function
+type checkFunc struct {
+	name         string
+	description  string
+	categories   []string
+	requirements Requirements
+	run          func(pass *Pass) []Violation
+}
+
+func (c *checkFunc) Name() string               { return c.name }
+func (c *checkFunc) Description() string        { return c.description }
+func (c *checkFunc) Categories() []string       { return c.categories }
+func (c *checkFunc) Requirements() Requirements { return c.requirements }
+func (c *checkFunc) Run(pass *Pass) []Violation { return c.run(pass) }
+
+// registry holds the registered checks by name
+var registry = make(map[string]Check)
+
+// RegisterCheck makes a check available by name; registering the same name
+// twice panics
+func RegisterCheck(check Check) {
+	if _, exists := registry[check.Name()]; exists {
+		panic(fmt.Sprintf("analyzer: check %q registered twice", check.Name()))
+	}
+	registry[check.Name()] = check
+}
+
+// RegisteredChecks returns the registered checks ordered by name
+func RegisteredChecks() []Check {
+	checks := make([]Check, 0, len(registry))
+	for _, check := range registry {
+		checks = append(checks, check)
+	}
+	sort.Slice(checks, func(i, j int) bool {
+		return checks[i].Name() < checks[j].Name()
+	})
+	return checks
+}
+
+// LookupCheck returns the check registered under name
+func LookupCheck(name string) (Check, bool) {
+	check, ok := registry[name]
+	return check, ok
+}
+
+func init() {
+	RegisterCheck(&checkFunc{
+		name:         "solid",
+		description:  "SOLID principle violations in functions, structs, interfaces and switches",
+		categories:   []string{"single-responsibility", "open-closed", "liskov-substitution", "interface-segregation", "dependency-inversion"},
+		requirements: Requirements{TypeInfo: true, Checks: []string{"panics"}},
+		run:          func(pass *Pass) []Violation { return pass.analyzer.runSOLIDAnalysis(pass) },
+	})
+	RegisterCheck(&checkFunc{
+		name:         "imports",
+		description:  "Import organization, style, unused, duplicate and shadowing imports",
+		categories:   []string{"import-organization", "import-style", "unused-import", "duplicate-import", "blank-import", "import-grouping", "import-shadowing"},
+		requirements: Requirements{TypeInfo: true},
+		run:          func(pass *Pass) []Violation { return pass.analyzer.runImportAnalysis() },
+	})
+	RegisterCheck(&checkFunc{
+		name:         "errors",
+		description:  "Error handling and fmt.Errorf calls that do not wrap errors",
+		categories:   []string{"error-handling", "error-wrapping"},
+		requirements: Requirements{TypeInfo: true},
+		run:          func(pass *Pass) []Violation { return pass.analyzer.runErrorAnalysis() },
+	})
+	RegisterCheck(&checkFunc{
+		name:         "goroutines",
+		description:  "Goroutine synchronization and context cancel functions that are never called",
+		categories:   []string{"concurrency", "context-leak"},
+		requirements: Requirements{TypeInfo: true},
+		run:          func(pass *Pass) []Violation { return pass.analyzer.runGoroutineAnalysis() },
+	})
+	RegisterCheck(&checkFunc{
+		name:        "channels",
+		description: "Complex functions using channels",
+		categories:  []string{"concurrency"},
+		run:         func(pass *Pass) []Violation { return pass.analyzer.runChannelAnalysis() },
+	})
+	RegisterCheck(&checkFunc{
+		name:        "dependencies",
+		description: "Package import cycles and layer violations",
+		categories:  []string{"import-cycle", "layer-violation"},
+		run:         func(pass *Pass) []Violation { return pass.analyzer.runDependencyAnalysis() },
+	})
+	RegisterCheck(&checkFunc{
+		name:         "invariants",
+		description:  "Invariant rules from .codeauditor.json",
+		categories:   []string{RuleImportBan, RuleModuleBoundary, RuleCallConstraint, RuleNaming, RuleASTPattern},
+		requirements: Requirements{TypeInfo: true},
+		run:          func(pass *Pass) []Violation { return pass.analyzer.runRuleAnalysis(pass) },
+	})
+	RegisterCheck(&checkFunc{
+		name:        "naming",
+		description: "Go naming idioms and the configured naming conventions",
+		categories:  []string{"mixed-caps", "initialisms", "package-stutter", "getter-prefix", "receiver-name", "interface-naming", "naming-convention"},
+		run:         func(pass *Pass) []Violation { return pass.analyzer.runNamingAnalysis(pass) },
+	})
+	RegisterCheck(&checkFunc{
+		name:         "resources",
+		description:  "Files and connections that are opened but never closed",
+		categories:   []string{"resource-leak"},
+		requirements: Requirements{TypeInfo: true, Checks: []string{"closes"}},
+		run:          runResourceLeaks,
+	})
+	RegisterCheck(&checkFunc{
+		name:         "exhaustive",
+		description:  "Switches over enum types that miss some of their values",
+		categories:   []string{"missing-enum-case"},
+		requirements: Requirements{TypeInfo: true, Checks: []string{"enums"}},
+		run:          runExhaustiveSwitches,
+	})
+
+	// Fact passes report nothing themselves; they export facts for other checks
+	RegisterCheck(&checkFunc{
+		name:         "panics",
+		description:  "Exports MayPanic facts for functions that can panic",
+		requirements: Requirements{TypeInfo: true},
+		run:          runPanicFacts,
+	})
+	RegisterCheck(&checkFunc{
+		name:         "closes",
+		description:  "Exports ClosesArgument facts for functions that close their parameters",
+		requirements: Requirements{TypeInfo: true},
+		run:          runCloseFacts,
+	})
+	RegisterCheck(&checkFunc{
+		name:         "enums",
+		description:  "Exports IsEnum facts for types with a declared set of constant values",
+		requirements: Requirements{TypeInfo: true},
+		run:          runEnumFacts,
+	})
+}
+
+// enabledChecks resolves the configured analyzer names, each once in the
+// order given, and reports unknown names as errors
+func (a *Analyzer) enabledChecks() ([]Check, []Error) {
+	var checks []Check
+	errors := []Error{}
+	seen := make(map[string]bool)
+
+	for _, name := range a.options.Analyzers {
+		if seen[name] {
+			continue
+		}
+		seen[name] = true
+
+		check, ok := LookupCheck(name)
+		if !ok {
+			errors = append(errors, Error{
+				Message: fmt.Sprintf("Unknown analyzer %q; available analyzers: %s", name, strings.Join(registeredCheckNames(), ", ")),
+				Type:    "analyzer",
+			})
+			continue
+		}
+		checks = append(checks, check)
+	}
+
+	return checks, errors
+}
+
+// registeredCheckNames returns the names of the registered checks that
+// report violations in order; fact passes only run as requirements
+func registeredCheckNames() []string {
+	var names []string
+	for _, check := range RegisteredChecks() {
+		if len(check.Categories()) == 0 {
+			continue
+		}
+		names = append(names, check.Name())
+	}
+	return names
+}
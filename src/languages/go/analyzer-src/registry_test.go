@@ -0,0 +1,84 @@
+package analyzer
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestEnabledChecks(t *testing.T) {
+	analyzer := NewAnalyzer(AnalysisOptions{Analyzers: []string{"naming", "solidd", "naming", "errors"}})
+
+	checks, errors := analyzer.enabledChecks()
+	if got, want := checkNames(checks), []string{"naming", "errors"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("checks %q, want %q", got, want)
+	}
+	if len(errors) != 1 || errors[0].Type != "analyzer" || !strings.HasPrefix(errors[0].Message, `Unknown analyzer "solidd"; available analyzers: `) {
+		t.Fatalf("errors %+v, want one unknown analyzer error", errors)
+	}
+	available := strings.TrimPrefix(errors[0].Message, `Unknown analyzer "solidd"; available analyzers: `)
+	if got, want := strings.Split(available, ", "), registeredCheckNames(); !reflect.DeepEqual(got, want) {
+		t.Errorf("available analyzers %q, want %q", got, want)
+	}
+}
+
+func TestDuplicateAnalyzerRunsOnce(t *testing.T) {
+	filePath := filepath.Join(t.TempDir(), "sample.go")
+	result := analyzeWritten(t, filePath, `package sample
+
+var snake_case = 1
+`, AnalysisOptions{Analyzers: []string{"naming", "naming"}})
+
+	if len(result.Violations) != 1 {
+		t.Errorf("got %d violations, want the naming finding once: %+v", len(result.Violations), result.Violations)
+	}
+	if len(result.Errors) != 0 {
+		t.Errorf("unexpected errors %+v", result.Errors)
+	}
+}
+
+func TestAnalyzeAndAnalyzeOverlayAgree(t *testing.T) {
+	source := `package sample
+
+import (
+	"fmt"
+	"os"
+)
+
+type Reader interface {
+	Read() string
+}
+
+var snake_case = 1
+
+func load(path string) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return fmt.Errorf("open %s: %v", path, err)
+	}
+	f.Stat()
+	return nil
+}
+`
+	filePath := filepath.Join(t.TempDir(), "sample.go")
+	options := AnalysisOptions{Analyzers: []string{"solid", "imports", "errors", "naming", "resources"}}
+	onDisk := analyzeWritten(t, filePath, source, options)
+
+	// The overlay replaces different content on disk
+	if err := os.WriteFile(filePath, []byte("package sample\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	overlaid, err := NewAnalyzer(options).AnalyzeOverlay(map[string]string{filePath: source})
+	if err != nil {
+		t.Fatalf("overlay analysis failed: %v", err)
+	}
+
+	if len(onDisk.Violations) == 0 {
+		t.Fatal("the sample reports nothing")
+	}
+	if !reflect.DeepEqual(overlaid.Violations, onDisk.Violations) {
+		t.Errorf("overlay violations differ\n got: %+v\nwant: %+v", overlaid.Violations, onDisk.Violations)
+	}
+}
@@ -0,0 +1,193 @@
+package analyzer
+
+import (
+	"fmt"
+	"go/ast"
+	"go/types"
+)
+
+// resourceOpeners are the standard library functions whose first result the
+// caller must close, by full name
+var resourceOpeners = map[string]bool{
+	"os.Open":         true,
+	"os.OpenFile":     true,
+	"os.Create":       true,
+	"os.CreateTemp":   true,
+	"net.Dial":        true,
+	"net.DialTimeout": true,
+	"net.Listen":      true,
+}
+
+// runResourceLeaks reports files and connections opened into a local
+// variable that the function neither closes, hands back to its caller or
+// stores, nor passes to a function that closes it (a ClosesArgument fact).
+// Without type information openers cannot be told apart and nothing is
+// reported.
+func runResourceLeaks(pass *Pass) []Violation {
+	var violations []Violation
+
+	for filePath, file := range pass.Parser.files {
+		typeInfo := pass.Parser.TypeInfo(filePath)
+		if typeInfo == nil || typeInfo.Pkg == nil {
+			continue
+		}
+		info := typeInfo.Info
+
+		ast.Inspect(file, func(n ast.Node) bool {
+			var body *ast.BlockStmt
+			switch node := n.(type) {
+			case *ast.FuncDecl:
+				body = node.Body
+			case *ast.FuncLit:
+				body = node.Body
+			}
+			if body == nil {
+				return true
+			}
+
+			for _, stmt := range statementsOf(body) {
+				assign, ok := stmt.(*ast.AssignStmt)
+				if !ok || len(assign.Rhs) != 1 || len(assign.Lhs) == 0 {
+					continue
+				}
+				call, ok := assign.Rhs[0].(*ast.CallExpr)
+				if !ok {
+					continue
+				}
+				opener, ok := calleeObject(call, info).(*types.Func)
+				if !ok || !resourceOpeners[opener.FullName()] {
+					continue
+				}
+				resource, ok := assign.Lhs[0].(*ast.Ident)
+				if !ok {
+					continue // Stored in a field or element
+				}
+				if resource.Name != "_" && resourceReleased(pass, body, assign, info.ObjectOf(resource), info) {
+					continue
+				}
+				violations = append(violations, resourceLeakViolation(pass.Parser, filePath, resource, opener))
+			}
+			return true
+		})
+	}
+
+	return violations
+}
+
+// resourceLeakViolation reports one resource that is never closed
+func resourceLeakViolation(p *Parser, filePath string, resource *ast.Ident, opener *types.Func) Violation {
+	message := fmt.Sprintf("%s opened by %s.%s is never closed", resource.Name, opener.Pkg().Name(), opener.Name())
+	if resource.Name == "_" {
+		message = fmt.Sprintf("The result of %s.%s is discarded without being closed", opener.Pkg().Name(), opener.Name())
+	}
+	return p.withRange(Violation{
+		File:     filePath,
+		Severity: "warning",
+		Message:  message,
+		Details: map[string]interface{}{
+			"resource": resource.Name,
+			"opener":   opener.FullName(),
+		},
+		Suggestion: "Close the resource when done, usually with defer right after checking the error",
+		Analyzer:   "resources",
+		Category:   "resource-leak",
+	}, resource.Pos(), resource.End())
+}
+
+// resourceReleased reports whether the function body closes resource, lets
+// it escape through a return, assignment, send or composite literal, or
+// passes it to a function known to close that argument
+func resourceReleased(pass *Pass, body *ast.BlockStmt, opened *ast.AssignStmt, resource types.Object, info *types.Info) bool {
+	if resource == nil {
+		return true
+	}
+	escapes := func(exprs ...ast.Expr) bool {
+		for _, expr := range exprs {
+			if flowsTo(expr, resource, info) {
+				return true
+			}
+		}
+		return false
+	}
+
+	released := false
+	ast.Inspect(body, func(n ast.Node) bool {
+		switch node := n.(type) {
+		case *ast.CallExpr:
+			if selector, ok := unparen(node.Fun).(*ast.SelectorExpr); ok && selector.Sel.Name == "Close" && refersTo(selector.X, resource, info) {
+				released = true
+			}
+			callee, known := calleeObject(node, info).(*types.Func)
+			if _, builtin := calleeObject(node, info).(*types.Builtin); builtin {
+				break
+			}
+			for argument, arg := range node.Args {
+				if !refersTo(arg, resource, info) {
+					continue
+				}
+				// Function values and calls without type information may
+				// close it; only known functions are followed
+				var closes ClosesArgument
+				if !known || pass.ImportObjectFact(callee, &closes) && containsInt(closes.Params, argument) {
+					released = true
+				}
+			}
+		case *ast.ReturnStmt:
+			released = released || escapes(node.Results...)
+		case *ast.AssignStmt:
+			released = released || node != opened && escapes(node.Rhs...)
+		case *ast.ValueSpec:
+			released = released || escapes(node.Values...)
+		case *ast.SendStmt:
+			released = released || escapes(node.Value)
+		case *ast.CompositeLit:
+			released = released || escapes(node.Elts...)
+		}
+		return !released
+	})
+	return released
+}
+
+// flowsTo reports whether the value of expr is resource itself, or holds it
+// through an address, a composite literal or an append
+func flowsTo(expr ast.Expr, resource types.Object, info *types.Info) bool {
+	switch node := unparen(expr).(type) {
+	case *ast.Ident:
+		return info.Uses[node] == resource
+	case *ast.UnaryExpr:
+		return flowsTo(node.X, resource, info)
+	case *ast.KeyValueExpr:
+		return flowsTo(node.Value, resource, info)
+	case *ast.CompositeLit:
+		for _, element := range node.Elts {
+			if flowsTo(element, resource, info) {
+				return true
+			}
+		}
+	case *ast.CallExpr:
+		if builtin, ok := calleeObject(node, info).(*types.Builtin); ok && builtin.Name() == "append" {
+			for _, arg := range node.Args {
+				if flowsTo(arg, resource, info) {
+					return true
+				}
+			}
+		}
+	}
+	return false
+}
+
+// refersTo reports whether expr is an identifier for resource
+func refersTo(expr ast.Expr, resource types.Object, info *types.Info) bool {
+	ident, ok := unparen(expr).(*ast.Ident)
+	return ok && info.Uses[ident] == resource
+}
+
+// containsInt reports whether values contains value
+func containsInt(values []int, value int) bool {
+	for _, candidate := range values {
+		if candidate == value {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,129 @@
+package analyzer
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+)
+
+// Rule kinds shared with the TypeScript invariant rules in .codeauditor.json
+const (
+	RuleImportBan      = "import-ban"
+	RuleModuleBoundary = "module-boundary"
+	RuleCallConstraint = "call-constraint"
+	RuleNaming         = "naming"
+	RuleASTPattern     = "ast-pattern"
+)
+
+// RuleAnalyzer enforces invariant rules from .codeauditor.json against Go code
+type RuleAnalyzer struct {
+	parser *Parser
+	rules  []Rule
+	errors []Error
+}
+
+// NewRuleAnalyzer creates a new invariant rule analyzer
+func NewRuleAnalyzer(parser *Parser, rules []Rule) *RuleAnalyzer {
+	return &RuleAnalyzer{
+		parser: parser,
+		rules:  rules,
+	}
+}
+
+// Analyze checks every rule that applies to Go; kinds only meaningful to
+// other languages (style-mechanism, no-raw-values) are skipped
+func (r *RuleAnalyzer) Analyze() []Violation {
+	var violations []Violation
+
+	for _, rule := range r.rules {
+		if rule.Language != "" && rule.Language != "go" {
+			continue
+		}
+		switch rule.Kind {
+		case RuleImportBan:
+			violations = append(violations, r.analyzeImportBan(rule)...)
+		case RuleModuleBoundary:
+			violations = append(violations, r.analyzeModuleBoundary(rule)...)
+		case RuleCallConstraint:
+			violations = append(violations, r.analyzeCallConstraint(rule)...)
+		case RuleNaming:
+			violations = append(violations, r.analyzeNaming(rule)...)
+		case RuleASTPattern:
+			violations = append(violations, r.analyzeASTPattern(rule)...)
+		}
+	}
+
+	return violations
+}
+
+// Errors returns the rules that could not be checked, such as rules with
+// an invalid pattern
+func (r *RuleAnalyzer) Errors() []Error {
+	return r.errors
+}
+
+// ruleError records that a rule could not be checked
+func (r *RuleAnalyzer) ruleError(rule Rule, problem string) {
+	r.errors = append(r.errors, Error{
+		Message: fmt.Sprintf("Rule %q: %s", rule.ID, problem),
+		Type:    "rule",
+	})
+}
+
+// ruleViolation fills the fields common to every rule violation
+func (r *RuleAnalyzer) ruleViolation(rule Rule, filePath, defaultMessage string, details map[string]interface{}) Violation {
+	message := rule.Message
+	if message == "" {
+		message = defaultMessage
+	}
+	severity := rule.Severity
+	if severity == "" {
+		severity = "warning"
+	}
+
+	details["ruleId"] = rule.ID
+	details["kind"] = rule.Kind
+	return Violation{
+		File:     filePath,
+		Severity: severity,
+		Message:  message,
+		Details:  details,
+		Analyzer: "invariants",
+		Category: rule.Kind,
+	}
+}
+
+// relativePath returns filePath relative to the configured root directory,
+// or to its module root, using forward slashes for glob matching
+func (p *Parser) relativePath(filePath string) string {
+	absPath, err := filepath.Abs(filePath)
+	if err != nil {
+		return filepath.ToSlash(filePath)
+	}
+
+	root := p.options.RootDir
+	if root == "" {
+		if module := p.ModuleFor(filePath); module != nil {
+			root = module.Dir
+		}
+	}
+	if root == "" {
+		return filepath.ToSlash(filePath)
+	}
+
+	absRoot, err := filepath.Abs(root)
+	if err != nil {
+		return filepath.ToSlash(filePath)
+	}
+	rel, err := filepath.Rel(absRoot, absPath)
+	if err != nil || strings.HasPrefix(rel, "..") {
+		return filepath.ToSlash(filePath)
+	}
+	return filepath.ToSlash(rel)
+}
+
+// matchesFileOrPackage reports whether a glob matches a file by its relative
+// path or by the import path of its package
+func (p *Parser) matchesFileOrPackage(pattern, filePath string) bool {
+	return matchGlob(pattern, p.relativePath(filePath)) || matchGlob(pattern, p.ImportPathFor(filePath))
+}
@@ -0,0 +1,177 @@
+package analyzer
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// defaultThresholds are the values findings are reported above, keyed by
+// "analyzer/category" and threshold name
+var defaultThresholds = map[string]map[string]int{
+	"solid/single-responsibility": {"functionResponsibilities": 3, "structResponsibilities": 5},
+	"solid/open-closed":           {"switchCases": 5},
+	"solid/interface-segregation": {"interfaceMethods": 5},
+	"solid/dependency-inversion":  {"concreteDependencies": 3},
+	"imports/import-organization": {"importCount": 10},
+	"channels/concurrency":        {"complexity": 3},
+}
+
+// validSeverities are the severities a setting may override findings with
+var validSeverities = map[string]bool{"suggestion": true, "warning": true, "critical": true}
+
+// settingsFor resolves the settings of one analyzer category in a file:
+// analyzer settings, then category settings, then matching path overrides
+// in order, later values replacing earlier ones
+func (p *Parser) settingsFor(filePath, analyzer, category string) AnalyzerSettings {
+	key := analyzer + "/" + category
+	resolved := AnalyzerSettings{Thresholds: make(map[string]int)}
+	for name, value := range defaultThresholds[key] {
+		resolved.Thresholds[name] = value
+	}
+
+	mergeSettings(&resolved, p.options.Settings[analyzer])
+	mergeSettings(&resolved, p.options.Settings[key])
+	for _, override := range p.options.Overrides {
+		if filePath == "" || !p.matchesFileOrPackage(override.Path, filePath) {
+			continue
+		}
+		mergeSettings(&resolved, override.Settings[analyzer])
+		mergeSettings(&resolved, override.Settings[key])
+	}
+	return resolved
+}
+
+// threshold returns the configured value a finding must exceed to be reported
+func (p *Parser) threshold(filePath, analyzer, category, name string) int {
+	return p.settingsFor(filePath, analyzer, category).Thresholds[name]
+}
+
+// applySettings drops the violations of disabled analyzers and categories
+// and applies severity overrides
+func (p *Parser) applySettings(violations []Violation) []Violation {
+	if len(p.options.Settings) == 0 && len(p.options.Overrides) == 0 {
+		return violations
+	}
+
+	kept := []Violation{}
+	for _, violation := range violations {
+		settings := p.settingsFor(violation.File, violation.Analyzer, violation.Category)
+		if settings.Enabled != nil && !*settings.Enabled {
+			continue
+		}
+		if settings.Severity != "" {
+			violation.Severity = settings.Severity
+		}
+		kept = append(kept, violation)
+	}
+	return kept
+}
+
+// effectiveSettings returns the settings each known category and each
+// configured key resolve to outside any path override
+func (p *Parser) effectiveSettings() EffectiveSettings {
+	effective := EffectiveSettings{
+		Settings:  make(map[string]AnalyzerSettings),
+		Overrides: p.options.Overrides,
+	}
+
+	for key := range defaultThresholds {
+		analyzer, category := splitSettingsKey(key)
+		effective.Settings[key] = p.settingsFor("", analyzer, category)
+	}
+	for key, settings := range p.options.Settings {
+		if _, ok := effective.Settings[key]; ok {
+			continue
+		}
+		if analyzer, category := splitSettingsKey(key); category != "" {
+			effective.Settings[key] = p.settingsFor("", analyzer, category)
+		} else {
+			effective.Settings[key] = settings
+		}
+	}
+	return effective
+}
+
+// settingsErrors reports invalid severities and unknown threshold names
+func (p *Parser) settingsErrors() []Error {
+	errors := []Error{}
+	check := func(scope string, settings map[string]AnalyzerSettings) {
+		keys := make([]string, 0, len(settings))
+		for key := range settings {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+
+		for _, key := range keys {
+			if severity := settings[key].Severity; severity != "" && !validSeverities[severity] {
+				errors = append(errors, Error{
+					Message: fmt.Sprintf("Settings %s%s: unknown severity %q", scope, key, severity),
+					Type:    "settings",
+				})
+			}
+			for _, name := range sortedThresholdNames(settings[key].Thresholds) {
+				if !knownThreshold(key, name) {
+					errors = append(errors, Error{
+						Message: fmt.Sprintf("Settings %s%s: unknown threshold %q", scope, key, name),
+						Type:    "settings",
+					})
+				}
+			}
+		}
+	}
+
+	check("", p.options.Settings)
+	for _, override := range p.options.Overrides {
+		check(fmt.Sprintf("override %q ", override.Path), override.Settings)
+	}
+	return errors
+}
+
+// mergeSettings copies the values set in next over resolved. Unknown
+// severities are left out: settingsErrors reports them, and applying one
+// would filter the finding out instead.
+func mergeSettings(resolved *AnalyzerSettings, next AnalyzerSettings) {
+	if next.Enabled != nil {
+		resolved.Enabled = next.Enabled
+	}
+	if validSeverities[next.Severity] {
+		resolved.Severity = next.Severity
+	}
+	for name, value := range next.Thresholds {
+		resolved.Thresholds[name] = value
+	}
+}
+
+// knownThreshold reports whether a settings key has a threshold of that name
+func knownThreshold(key, name string) bool {
+	analyzer, category := splitSettingsKey(key)
+	for defaultKey, thresholds := range defaultThresholds {
+		defaultAnalyzer, defaultCategory := splitSettingsKey(defaultKey)
+		if defaultAnalyzer != analyzer || (category != "" && defaultCategory != category) {
+			continue
+		}
+		if _, ok := thresholds[name]; ok {
+			return true
+		}
+	}
+	return false
+}
+
+// splitSettingsKey splits "analyzer/category" into its parts
+func splitSettingsKey(key string) (string, string) {
+	if index := strings.Index(key, "/"); index >= 0 {
+		return key[:index], key[index+1:]
+	}
+	return key, ""
+}
+
+// sortedThresholdNames returns threshold names in ascending order
+func sortedThresholdNames(thresholds map[string]int) []string {
+	names := make([]string, 0, len(thresholds))
+	for name := range thresholds {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
@@ -0,0 +1,156 @@
+package analyzer
+
+import (
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// enabled returns a pointer to an Enabled setting
+func enabled(value bool) *bool {
+	return &value
+}
+
+func TestSettingsForPrecedence(t *testing.T) {
+	root := t.TempDir()
+	parser := NewParser(AnalysisOptions{
+		RootDir: root,
+		Settings: map[string]AnalyzerSettings{
+			"solid":                       {Severity: "suggestion", Thresholds: map[string]int{"switchCases": 8}},
+			"solid/open-closed":           {Severity: "warning"},
+			"solid/interface-segregation": {Enabled: enabled(false)},
+		},
+		Overrides: []SettingsOverride{
+			{Path: "internal/**", Settings: map[string]AnalyzerSettings{
+				"solid/open-closed": {Thresholds: map[string]int{"switchCases": 20}},
+			}},
+			{Path: "internal/legacy/*.go", Settings: map[string]AnalyzerSettings{
+				"solid":                       {Severity: "critical"},
+				"solid/interface-segregation": {Enabled: enabled(true)},
+			}},
+		},
+	})
+	file := func(name string) string {
+		return filepath.Join(root, filepath.FromSlash(name))
+	}
+
+	tests := []struct {
+		name     string
+		file     string
+		category string
+		want     AnalyzerSettings
+	}{
+		{
+			name:     "category over analyzer",
+			file:     file("main.go"),
+			category: "open-closed",
+			want:     AnalyzerSettings{Severity: "warning", Thresholds: map[string]int{"switchCases": 8}},
+		},
+		{
+			name:     "analyzer settings without category settings",
+			file:     file("main.go"),
+			category: "single-responsibility",
+			want: AnalyzerSettings{Severity: "suggestion", Thresholds: map[string]int{
+				"functionResponsibilities": 3, "structResponsibilities": 5, "switchCases": 8,
+			}},
+		},
+		{
+			name:     "path override over configured settings",
+			file:     file("internal/api/handler.go"),
+			category: "open-closed",
+			want:     AnalyzerSettings{Severity: "warning", Thresholds: map[string]int{"switchCases": 20}},
+		},
+		{
+			name:     "later overrides over earlier ones",
+			file:     file("internal/legacy/old.go"),
+			category: "open-closed",
+			want:     AnalyzerSettings{Severity: "critical", Thresholds: map[string]int{"switchCases": 20}},
+		},
+		{
+			name:     "override enables a disabled category",
+			file:     file("internal/legacy/old.go"),
+			category: "interface-segregation",
+			want:     AnalyzerSettings{Enabled: enabled(true), Severity: "critical", Thresholds: map[string]int{"interfaceMethods": 5, "switchCases": 8}},
+		},
+		{
+			name:     "no file applies no override",
+			category: "interface-segregation",
+			want:     AnalyzerSettings{Enabled: enabled(false), Severity: "suggestion", Thresholds: map[string]int{"interfaceMethods": 5, "switchCases": 8}},
+		},
+	}
+
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			if got := parser.settingsFor(test.file, "solid", test.category); !reflect.DeepEqual(got, test.want) {
+				t.Errorf("got %+v, want %+v", got, test.want)
+			}
+		})
+	}
+}
+
+func TestThreshold(t *testing.T) {
+	parser := NewParser(AnalysisOptions{Settings: map[string]AnalyzerSettings{
+		"imports/import-organization": {Thresholds: map[string]int{"importCount": 4}},
+	}})
+
+	tests := []struct {
+		analyzer, category, name string
+		want                     int
+	}{
+		{"imports", "import-organization", "importCount", 4},
+		{"solid", "dependency-inversion", "concreteDependencies", 3},
+		{"channels", "concurrency", "complexity", 3},
+		{"solid", "dependency-inversion", "unknown", 0},
+	}
+	for _, test := range tests {
+		if got := parser.threshold("main.go", test.analyzer, test.category, test.name); got != test.want {
+			t.Errorf("threshold(%s/%s %s) = %d, want %d", test.analyzer, test.category, test.name, got, test.want)
+		}
+	}
+}
+
+func TestUnknownSeverityIsReportedAndIgnored(t *testing.T) {
+	filePath := filepath.Join(t.TempDir(), "sample.go")
+	result := analyzeWritten(t, filePath, `package sample
+
+var snake_case = 1
+`, AnalysisOptions{
+		Analyzers: []string{"naming"},
+		Settings:  map[string]AnalyzerSettings{"naming": {Severity: "critical"}, "naming/mixed-caps": {Severity: "error"}},
+	})
+
+	if len(result.Violations) != 1 || result.Violations[0].Severity != "critical" {
+		t.Errorf("violations %+v, want the finding kept with the analyzer severity", result.Violations)
+	}
+	if len(result.Errors) != 1 || result.Errors[0].Type != "settings" || !strings.Contains(result.Errors[0].Message, `unknown severity "error"`) {
+		t.Errorf("errors %+v, want the unknown severity reported", result.Errors)
+	}
+}
+
+func TestUnknownThresholdIsReported(t *testing.T) {
+	parser := NewParser(AnalysisOptions{
+		Settings: map[string]AnalyzerSettings{
+			"solid":    {Thresholds: map[string]int{"switchCases": 3, "lines": 10}},
+			"naming/x": {Thresholds: map[string]int{"importCount": 1}},
+			"imports":  {Thresholds: map[string]int{"importCount": 1}},
+		},
+		Overrides: []SettingsOverride{{Path: "gen/**", Settings: map[string]AnalyzerSettings{
+			"solid/open-closed": {Thresholds: map[string]int{"interfaceMethods": 2}},
+		}}},
+	})
+
+	var messages []string
+	for _, settingsError := range parser.settingsErrors() {
+		messages = append(messages, settingsError.Message)
+	}
+	want := []string{
+		`Settings naming/x: unknown threshold "importCount"`,
+		`Settings solid: unknown threshold "lines"`,
+		`Settings override "gen/**" solid/open-closed: unknown threshold "interfaceMethods"`,
+	}
+	if !reflect.DeepEqual(messages, want) {
+		t.Errorf("got %q, want %q", messages, want)
+	}
+}
@@ -0,0 +1,82 @@
+package analyzer
+
+import (
+	"bytes"
+	"fmt"
+	"go/token"
+	"strconv"
+	"strings"
+)
+
+// maxSnippetLines caps how many lines of a multi-line range are rendered
+const maxSnippetLines = 8
+
+// renderSnippet renders the source lines of a span with the configured
+// context lines and a caret row underlining the span on its first line
+func (p *Parser) renderSnippet(from, to token.Pos) string {
+	start := p.fileSet.Position(from)
+	src := p.sources[start.Filename]
+	if src == nil {
+		return ""
+	}
+
+	end := start
+	if to.IsValid() {
+		end = p.fileSet.Position(to)
+	}
+
+	lines := bytes.Split(src, []byte("\n"))
+	lastRangeLine := end.Line
+	if lastRangeLine-start.Line >= maxSnippetLines {
+		lastRangeLine = start.Line + maxSnippetLines - 1
+	}
+
+	first := start.Line - p.options.ContextLinesBefore
+	if first < 1 {
+		first = 1
+	}
+	last := lastRangeLine + p.options.ContextLinesAfter
+	if last > len(lines) {
+		last = len(lines)
+	}
+
+	width := len(strconv.Itoa(last))
+	var builder strings.Builder
+	for lineNumber := first; lineNumber <= last; lineNumber++ {
+		line := strings.TrimRight(string(lines[lineNumber-1]), "\r")
+		fmt.Fprintf(&builder, "%*d | %s\n", width, lineNumber, line)
+
+		if lineNumber == start.Line {
+			caretEnd := len(line) + 1
+			if end.Line == start.Line && end.Column > start.Column {
+				caretEnd = end.Column
+			}
+			fmt.Fprintf(&builder, "%*s | %s\n", width, "", caretRow(line, start.Column, caretEnd))
+		}
+	}
+
+	return strings.TrimRight(builder.String(), "\n")
+}
+
+// caretRow builds the marker row for byte columns [from, to) of line,
+// keeping tabs so the carets line up under the source
+func caretRow(line string, from, to int) string {
+	var builder strings.Builder
+	for i, r := range line {
+		column := i + 1
+		switch {
+		case column >= to:
+			return strings.TrimRight(builder.String(), " ")
+		case column >= from:
+			builder.WriteByte('^')
+		case r == '\t':
+			builder.WriteByte('\t')
+		default:
+			builder.WriteByte(' ')
+		}
+	}
+	if builder.Len() == 0 || !strings.Contains(builder.String(), "^") {
+		builder.WriteByte('^')
+	}
+	return builder.String()
+}
@@ -2,6 +2,7 @@ package analyzer
 
 import (
 	"go/ast"
+	"go/types"
 	"strings"
 )
 
@@ -11,6 +12,7 @@ type SOLIDAnalyzer struct {
 	functions []Function
 	structs   []Struct
 	interfaces []Interface
+	pass      *Pass // Facts of the required checks, when run as a check
 }
 
 // NewSOLIDAnalyzer creates a new SOLID analyzer
@@ -52,10 +54,9 @@ func (s *SOLIDAnalyzer) analyzeSRP() []Violation {
 	// Check functions for too many responsibilities
 	for _, function := range s.functions {
 		responsibilities := s.countFunctionResponsibilities(function)
-		if responsibilities > 3 {
-			violations = append(violations, Violation{
+		if responsibilities > s.parser.threshold(function.File, "solid", "single-responsibility", "functionResponsibilities") {
+			violations = append(violations, s.parser.withEntityRange(Violation{
 				File:     function.File,
-				Line:     function.StartLine,
 				Severity: "warning",
 				Message:  "Function has too many responsibilities",
 				Details: map[string]interface{}{
@@ -66,17 +67,16 @@ func (s *SOLIDAnalyzer) analyzeSRP() []Violation {
 				Suggestion: "Consider breaking this function into smaller, more focused functions",
 				Analyzer:   "solid",
 				Category:   "single-responsibility",
-			})
+			}, function.EntityInfo))
 		}
 	}
 
 	// Check structs for too many responsibilities
 	for _, structInfo := range s.structs {
 		responsibilities := s.countStructResponsibilities(structInfo)
-		if responsibilities > 5 {
-			violations = append(violations, Violation{
+		if responsibilities > s.parser.threshold(structInfo.File, "solid", "single-responsibility", "structResponsibilities") {
+			violations = append(violations, s.parser.withEntityRange(Violation{
 				File:     structInfo.File,
-				Line:     structInfo.StartLine,
 				Severity: "warning",
 				Message:  "Struct has too many responsibilities",
 				Details: map[string]interface{}{
@@ -88,7 +88,7 @@ func (s *SOLIDAnalyzer) analyzeSRP() []Violation {
 				Suggestion: "Consider splitting this struct into smaller, more cohesive structs",
 				Analyzer:   "solid",
 				Category:   "single-responsibility",
-			})
+			}, structInfo.EntityInfo))
 		}
 	}
 
@@ -101,15 +101,14 @@ func (s *SOLIDAnalyzer) analyzeOCP() []Violation {
 
 	// Check for large switch/case statements that could benefit from polymorphism
 	for filePath, file := range s.parser.files {
+		maxCases := s.parser.threshold(filePath, "solid", "open-closed", "switchCases")
 		ast.Inspect(file, func(n ast.Node) bool {
 			switch node := n.(type) {
 			case *ast.SwitchStmt:
 				caseCount := s.countSwitchCases(node)
-				if caseCount > 5 {
-					pos := s.parser.fileSet.Position(node.Pos())
-					violations = append(violations, Violation{
+				if caseCount > maxCases {
+					violations = append(violations, s.parser.withRange(Violation{
 						File:     filePath,
-						Line:     pos.Line,
 						Severity: "suggestion",
 						Message:  "Large switch statement detected - consider using polymorphism",
 						Details: map[string]interface{}{
@@ -119,15 +118,13 @@ func (s *SOLIDAnalyzer) analyzeOCP() []Violation {
 						Suggestion: "Consider using interfaces and polymorphism instead of large switch statements",
 						Analyzer:   "solid",
 						Category:   "open-closed",
-					})
+					}, node.Pos(), node.Body.Lbrace))
 				}
 			case *ast.TypeSwitchStmt:
 				caseCount := s.countTypeSwitchCases(node)
-				if caseCount > 5 {
-					pos := s.parser.fileSet.Position(node.Pos())
-					violations = append(violations, Violation{
+				if caseCount > maxCases {
+					violations = append(violations, s.parser.withRange(Violation{
 						File:     filePath,
-						Line:     pos.Line,
 						Severity: "suggestion",
 						Message:  "Large type switch detected - consider using interfaces",
 						Details: map[string]interface{}{
@@ -137,7 +134,7 @@ func (s *SOLIDAnalyzer) analyzeOCP() []Violation {
 						Suggestion: "Consider using interfaces with method dispatch instead of type switches",
 						Analyzer:   "solid",
 						Category:   "open-closed",
-					})
+					}, node.Pos(), node.Body.Lbrace))
 				}
 			}
 			return true
@@ -153,20 +150,23 @@ func (s *SOLIDAnalyzer) analyzeLSP() []Violation {
 
 	// Check for methods that panic or return errors in ways that violate LSP
 	for _, function := range s.functions {
-		if s.functionThrowsUnexpectedPanic(function) {
-			violations = append(violations, Violation{
+		if panics, via := s.functionThrowsUnexpectedPanic(function); panics {
+			details := map[string]interface{}{
+				"function":  function.Name,
+				"principle": "LSP",
+			}
+			if via != "" {
+				details["panicsVia"] = via
+			}
+			violations = append(violations, s.parser.withEntityRange(Violation{
 				File:     function.File,
-				Line:     function.StartLine,
 				Severity: "warning",
 				Message:  "Method may violate Liskov Substitution Principle by panicking",
-				Details: map[string]interface{}{
-					"function":  function.Name,
-					"principle": "LSP",
-				},
+				Details:  details,
 				Suggestion: "Consider returning an error instead of panicking to maintain substitutability",
 				Analyzer:   "solid",
 				Category:   "liskov-substitution",
-			})
+			}, function.EntityInfo))
 		}
 	}
 
@@ -179,10 +179,9 @@ func (s *SOLIDAnalyzer) analyzeISP() []Violation {
 
 	// Check for fat interfaces
 	for _, interfaceInfo := range s.interfaces {
-		if len(interfaceInfo.Methods) > 5 {
-			violations = append(violations, Violation{
+		if len(interfaceInfo.Methods) > s.parser.threshold(interfaceInfo.File, "solid", "interface-segregation", "interfaceMethods") {
+			violations = append(violations, s.parser.withEntityRange(Violation{
 				File:     interfaceInfo.File,
-				Line:     interfaceInfo.StartLine,
 				Severity: "warning",
 				Message:  "Interface has too many methods",
 				Details: map[string]interface{}{
@@ -193,7 +192,7 @@ func (s *SOLIDAnalyzer) analyzeISP() []Violation {
 				Suggestion: "Consider splitting this interface into smaller, more focused interfaces",
 				Analyzer:   "solid",
 				Category:   "interface-segregation",
-			})
+			}, interfaceInfo.EntityInfo))
 		}
 	}
 
@@ -207,10 +206,9 @@ func (s *SOLIDAnalyzer) analyzeDIP() []Violation {
 	// Check for direct dependencies on concrete types instead of interfaces
 	for _, structInfo := range s.structs {
 		concreteDeps := s.countConcreteDependencies(structInfo)
-		if concreteDeps > 3 {
-			violations = append(violations, Violation{
+		if concreteDeps > s.parser.threshold(structInfo.File, "solid", "dependency-inversion", "concreteDependencies") {
+			violations = append(violations, s.parser.withEntityRange(Violation{
 				File:     structInfo.File,
-				Line:     structInfo.StartLine,
 				Severity: "suggestion",
 				Message:  "Struct has many concrete dependencies",
 				Details: map[string]interface{}{
@@ -221,7 +219,7 @@ func (s *SOLIDAnalyzer) analyzeDIP() []Violation {
 				Suggestion: "Consider depending on interfaces instead of concrete types",
 				Analyzer:   "solid",
 				Category:   "dependency-inversion",
-			})
+			}, structInfo.EntityInfo))
 		}
 	}
 
@@ -321,14 +319,30 @@ func (s *SOLIDAnalyzer) countTypeSwitchCases(typeSwitchStmt *ast.TypeSwitchStmt)
 	return caseCount
 }
 
-func (s *SOLIDAnalyzer) functionThrowsUnexpectedPanic(function Function) bool {
-	// This is a simplified check - in a real implementation,
-	// we would analyze the AST for panic() calls
+// functionThrowsUnexpectedPanic reports whether a method may panic and
+// through which call, using the MayPanic facts when type information is
+// available and the method's name and documentation otherwise. Plain
+// functions have no substitutes, so they are never reported.
+func (s *SOLIDAnalyzer) functionThrowsUnexpectedPanic(function Function) (bool, string) {
+	if function.Receiver == "" {
+		return false, ""
+	}
+	if fn := s.parser.funcObject(function.EntityInfo); fn != nil && s.pass != nil {
+		var fact MayPanic
+		return s.pass.ImportObjectFact(fn, &fact), fact.Via
+	}
+
 	return strings.Contains(strings.ToLower(function.Name), "panic") ||
-		strings.Contains(strings.ToLower(function.Purpose), "panic")
+		strings.Contains(strings.ToLower(function.Purpose), "panic"), ""
 }
 
 func (s *SOLIDAnalyzer) countConcreteDependencies(structInfo Struct) int {
+	if typeName := s.parser.typeObject(structInfo.EntityInfo); typeName != nil {
+		if structType, ok := typeName.Type().Underlying().(*types.Struct); ok {
+			return countTypedConcreteDependencies(structType)
+		}
+	}
+
 	concreteDeps := 0
 
 	for _, field := range structInfo.Fields {
@@ -343,6 +357,35 @@ func (s *SOLIDAnalyzer) countConcreteDependencies(structInfo Struct) int {
 	return concreteDeps
 }
 
+// countTypedConcreteDependencies counts the fields of a type-checked struct
+// that hold concrete services
+func countTypedConcreteDependencies(structType *types.Struct) int {
+	concreteDeps := 0
+	for i := 0; i < structType.NumFields(); i++ {
+		if isConcreteDependency(structType.Field(i).Type()) {
+			concreteDeps++
+		}
+	}
+	return concreteDeps
+}
+
+// isConcreteDependency reports whether a field type is a service type of the
+// analyzed code rather than an interface or plain data: a named struct with
+// methods declared outside the standard library, or a pointer to one
+func isConcreteDependency(fieldType types.Type) bool {
+	if pointer, ok := fieldType.(*types.Pointer); ok {
+		fieldType = pointer.Elem()
+	}
+	named, ok := fieldType.(*types.Named)
+	if !ok || named.Obj().Pkg() == nil || isStdPackage(named.Obj().Pkg().Path()) {
+		return false
+	}
+	if _, ok := named.Underlying().(*types.Struct); !ok {
+		return false
+	}
+	return types.NewMethodSet(types.NewPointer(named)).Len() > 0
+}
+
 func (s *SOLIDAnalyzer) isBuiltinType(typeName string) bool {
 	builtinTypes := []string{
 		"bool", "string", "int", "int8", "int16", "int32", "int64",
@@ -0,0 +1,274 @@
+package analyzer
+
+import (
+	"fmt"
+	"go/ast"
+	"go/token"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Inline suppression directives:
+//
+//	//auditor:ignore <analyzer[/category]> [until=YYYY-MM-DD] <reason>
+//	//auditor:file-ignore <analyzer[/category]> [until=YYYY-MM-DD] <reason>
+//
+// The selector may be "*" to match every analyzer. An ignore directive
+// covers its own line when it trails code, the declaration it documents or
+// starts on, and otherwise the line that follows it.
+const (
+	ignoreDirective     = "//auditor:ignore"
+	fileIgnoreDirective = "//auditor:file-ignore"
+	expiryPrefix        = "until="
+	expiryLayout        = "2006-01-02"
+)
+
+// suppression is one parsed directive and the lines it covers
+type suppression struct {
+	file      string
+	directive string
+	analyzer  string
+	category  string
+	reason    string
+	expires   string
+	expired   bool
+	malformed string
+	fromLine  int
+	toLine    int
+	pos, end  token.Pos
+	used      bool
+}
+
+// applySuppressions moves violations covered by inline directives into the
+// suppressed list and reports expired, malformed and unused directives
+func (a *Analyzer) applySuppressions(violations []Violation) ([]Violation, []SuppressedViolation) {
+	kept := []Violation{}
+	suppressed := []SuppressedViolation{}
+	suppressions := a.parser.suppressions(time.Now())
+
+	for _, violation := range violations {
+		directive := findSuppression(suppressions[violation.File], violation)
+		if directive == nil {
+			kept = append(kept, violation)
+			continue
+		}
+		directive.used = true
+		suppressed = append(suppressed, SuppressedViolation{
+			Violation:     violation,
+			Reason:        directive.reason,
+			Directive:     directive.directive,
+			DirectiveLine: a.parser.fileSet.Position(directive.pos).Line,
+			Expires:       directive.expires,
+		})
+	}
+
+	for _, filePath := range sortedSuppressionFiles(suppressions) {
+		for _, directive := range suppressions[filePath] {
+			if violation, ok := a.suppressionViolation(directive); ok {
+				kept = append(kept, a.parser.applySettings([]Violation{violation})...)
+			}
+		}
+	}
+
+	return kept, suppressed
+}
+
+// findSuppression returns the first active directive covering a violation
+func findSuppression(directives []*suppression, violation Violation) *suppression {
+	for _, directive := range directives {
+		if directive.expired || directive.malformed != "" {
+			continue
+		}
+		if violation.Line < directive.fromLine || violation.Line > directive.toLine {
+			continue
+		}
+		if directive.analyzer != "*" && directive.analyzer != violation.Analyzer {
+			continue
+		}
+		if directive.category != "" && directive.category != "*" && directive.category != violation.Category {
+			continue
+		}
+		return directive
+	}
+	return nil
+}
+
+// suppressionViolation reports a directive that is malformed, expired or,
+// when its analyzer ran, matched no violation
+func (a *Analyzer) suppressionViolation(directive *suppression) (Violation, bool) {
+	violation := Violation{
+		File:     directive.file,
+		Details:  map[string]interface{}{"directive": directive.directive},
+		Analyzer: "suppressions",
+	}
+
+	switch {
+	case directive.malformed != "":
+		violation.Severity = "warning"
+		violation.Message = "Malformed suppression directive: " + directive.malformed
+		violation.Suggestion = "Write " + ignoreDirective + " <analyzer[/category]> [until=YYYY-MM-DD] <reason>"
+		violation.Category = "malformed-suppression"
+	case directive.expired:
+		violation.Severity = "warning"
+		violation.Message = fmt.Sprintf("Suppression of %s expired on %s", directive.selector(), directive.expires)
+		violation.Suggestion = "Fix the suppressed findings or extend the expiry date"
+		violation.Category = "expired-suppression"
+		violation.Details["expires"] = directive.expires
+	case !directive.used && a.analyzerEnabled(directive.analyzer):
+		violation.Severity = "suggestion"
+		violation.Message = fmt.Sprintf("Suppression of %s matches no finding", directive.selector())
+		violation.Suggestion = "Remove the unused suppression directive"
+		violation.Category = "unused-suppression"
+	default:
+		return Violation{}, false
+	}
+
+	violation = a.parser.withRange(violation, directive.pos, directive.end)
+	violation.Fingerprint = a.parser.fingerprint(violation)
+	return violation, true
+}
+
+// analyzerEnabled reports whether an analyzer ran, "*" meaning any
+func (a *Analyzer) analyzerEnabled(name string) bool {
+	for _, analyzerName := range a.options.Analyzers {
+		if name == "*" || analyzerName == name {
+			return true
+		}
+	}
+	return false
+}
+
+// selector renders the analyzer and category a directive applies to
+func (s *suppression) selector() string {
+	if s.category == "" {
+		return s.analyzer
+	}
+	return s.analyzer + "/" + s.category
+}
+
+// suppressions parses the directives of every parsed file, keyed by file path
+func (p *Parser) suppressions(now time.Time) map[string][]*suppression {
+	suppressions := make(map[string][]*suppression)
+
+	for filePath, file := range p.files {
+		if p.IsContextFile(filePath) {
+			continue
+		}
+		for _, group := range file.Comments {
+			for _, comment := range group.List {
+				directive := p.parseSuppression(filePath, file, group, comment, now)
+				if directive != nil {
+					suppressions[filePath] = append(suppressions[filePath], directive)
+				}
+			}
+		}
+	}
+
+	return suppressions
+}
+
+// parseSuppression parses one comment, returning nil when it is not a directive
+func (p *Parser) parseSuppression(filePath string, file *ast.File, group *ast.CommentGroup, comment *ast.Comment, now time.Time) *suppression {
+	text := comment.Text
+	fileWide := strings.HasPrefix(text, fileIgnoreDirective+" ") || text == fileIgnoreDirective
+	if !fileWide && !strings.HasPrefix(text, ignoreDirective+" ") && text != ignoreDirective {
+		return nil
+	}
+
+	directive := &suppression{
+		file:      filePath,
+		directive: text,
+		pos:       comment.Pos(),
+		end:       comment.End(),
+	}
+
+	fields := strings.Fields(text)[1:]
+	if len(fields) == 0 {
+		directive.malformed = "missing analyzer selector"
+		return directive
+	}
+	selector := fields[0]
+	fields = fields[1:]
+	directive.analyzer = selector
+	if index := strings.Index(selector, "/"); index >= 0 {
+		directive.analyzer, directive.category = selector[:index], selector[index+1:]
+	}
+
+	if len(fields) > 0 && strings.HasPrefix(fields[0], expiryPrefix) {
+		directive.expires = strings.TrimPrefix(fields[0], expiryPrefix)
+		fields = fields[1:]
+		expires, err := time.Parse(expiryLayout, directive.expires)
+		if err != nil {
+			directive.malformed = fmt.Sprintf("invalid expiry date %q", directive.expires)
+			return directive
+		}
+		// The directive stays active through the whole expiry day
+		directive.expired = !now.Before(expires.AddDate(0, 0, 1))
+	}
+	directive.reason = strings.Join(fields, " ")
+
+	if fileWide {
+		directive.fromLine, directive.toLine = 0, p.fileSet.File(file.Pos()).LineCount()
+		return directive
+	}
+	directive.fromLine, directive.toLine = p.suppressionScope(filePath, file, group, comment)
+	return directive
+}
+
+// suppressionScope returns the lines covered by an ignore directive
+func (p *Parser) suppressionScope(filePath string, file *ast.File, group *ast.CommentGroup, comment *ast.Comment) (int, int) {
+	line := p.fileSet.Position(comment.Pos()).Line
+
+	// The declaration documented by the directive, or starting on its line
+	var scope ast.Node
+	ast.Inspect(file, func(n ast.Node) bool {
+		if n == nil || scope != nil {
+			return false
+		}
+		if n.End() < comment.Pos() && n != ast.Node(file) {
+			return false
+		}
+		var doc *ast.CommentGroup
+		switch node := n.(type) {
+		case *ast.FuncDecl:
+			doc = node.Doc
+		case *ast.GenDecl:
+			doc = node.Doc
+		case *ast.TypeSpec:
+			doc = node.Doc
+		case *ast.ValueSpec:
+			doc = node.Doc
+		case *ast.Field:
+			doc = node.Doc
+		default:
+			return true
+		}
+		if doc == group || p.fileSet.Position(n.Pos()).Line == line {
+			scope = n
+			return false
+		}
+		return true
+	})
+	if scope != nil {
+		return p.fileSet.Position(scope.Pos()).Line, p.fileSet.Position(scope.End()).Line
+	}
+
+	// A directive trailing code covers its own line, otherwise the next one
+	lineStart := p.fileSet.Position(p.fileSet.File(comment.Pos()).LineStart(line)).Offset
+	prefix := p.sources[filePath][lineStart:p.fileSet.Position(comment.Pos()).Offset]
+	if strings.TrimSpace(string(prefix)) != "" {
+		return line, line
+	}
+	return line + 1, line + 1
+}
+
+// sortedSuppressionFiles returns the files with directives in ascending order
+func sortedSuppressionFiles(suppressions map[string][]*suppression) []string {
+	files := make([]string, 0, len(suppressions))
+	for filePath := range suppressions {
+		files = append(files, filePath)
+	}
+	sort.Strings(files)
+	return files
+}
@@ -0,0 +1,174 @@
+package analyzer
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+// finding is the part of a violation the suppression tests compare
+type finding struct {
+	Line     int
+	Category string
+}
+
+// analyzeSource runs the given analyzers over one file written to a
+// temporary directory
+func analyzeSource(t *testing.T, source string, analyzers ...string) *AnalysisResult {
+	t.Helper()
+	filePath := filepath.Join(t.TempDir(), "sample.go")
+	if err := os.WriteFile(filePath, []byte(source), 0644); err != nil {
+		t.Fatal(err)
+	}
+	result, err := NewAnalyzer(AnalysisOptions{Analyzers: analyzers}).Analyze([]string{filePath})
+	if err != nil {
+		t.Fatalf("analysis failed: %v", err)
+	}
+	return result
+}
+
+func TestSuppressions(t *testing.T) {
+	tests := []struct {
+		name       string
+		source     string
+		kept       []finding
+		suppressed []finding
+	}{
+		{
+			name: "line before",
+			source: `package sample
+
+//auditor:ignore naming/mixed-caps generated binding
+var snake_case = 1
+`,
+			suppressed: []finding{{4, "mixed-caps"}},
+		},
+		{
+			name: "trailing",
+			source: `package sample
+
+var snake_case = 1 //auditor:ignore naming legacy name
+var other_case = 2
+`,
+			kept:       []finding{{4, "mixed-caps"}},
+			suppressed: []finding{{3, "mixed-caps"}},
+		},
+		{
+			name: "documented declaration",
+			source: `package sample
+
+// Values documents the block.
+//auditor:ignore naming legacy names
+var (
+	snake_case = 1
+	other_case = 2
+)
+
+var third_case = 3
+`,
+			kept:       []finding{{10, "mixed-caps"}},
+			suppressed: []finding{{6, "mixed-caps"}, {7, "mixed-caps"}},
+		},
+		{
+			name: "file wide",
+			source: `//auditor:file-ignore * vendored code
+package sample
+
+var snake_case = 1
+
+func do_thing() {}
+`,
+			suppressed: []finding{{4, "mixed-caps"}, {6, "mixed-caps"}},
+		},
+		{
+			name: "other category",
+			source: `package sample
+
+//auditor:ignore naming/getter-prefix not a getter
+var snake_case = 1
+`,
+			kept: []finding{{3, "unused-suppression"}, {4, "mixed-caps"}},
+		},
+		{
+			name: "expired",
+			source: `package sample
+
+//auditor:ignore naming until=2000-01-01 temporary
+var snake_case = 1
+`,
+			kept: []finding{{3, "expired-suppression"}, {4, "mixed-caps"}},
+		},
+		{
+			name: "not yet expired",
+			source: `package sample
+
+//auditor:ignore naming until=2999-12-31 temporary
+var snake_case = 1
+`,
+			suppressed: []finding{{4, "mixed-caps"}},
+		},
+		{
+			name: "malformed",
+			source: `package sample
+
+//auditor:ignore naming until=soon temporary
+var snake_case = 1
+
+//auditor:ignore
+var other_case = 2
+`,
+			kept: []finding{{3, "malformed-suppression"}, {4, "mixed-caps"}, {6, "malformed-suppression"}, {7, "mixed-caps"}},
+		},
+		{
+			name: "analyzer not run",
+			source: `package sample
+
+//auditor:ignore imports/dot-import test helper
+var snake_case = 1
+`,
+			kept: []finding{{4, "mixed-caps"}},
+		},
+	}
+
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			result := analyzeSource(t, test.source, "naming")
+
+			var kept, suppressed []finding
+			for _, violation := range result.Violations {
+				kept = append(kept, finding{violation.Line, violation.Category})
+			}
+			for _, violation := range result.Suppressed {
+				suppressed = append(suppressed, finding{violation.Line, violation.Category})
+			}
+			if !reflect.DeepEqual(kept, test.kept) {
+				t.Errorf("kept %v, want %v", kept, test.kept)
+			}
+			if !reflect.DeepEqual(suppressed, test.suppressed) {
+				t.Errorf("suppressed %v, want %v", suppressed, test.suppressed)
+			}
+		})
+	}
+}
+
+func TestSuppressedViolationRecordsDirective(t *testing.T) {
+	result := analyzeSource(t, `package sample
+
+//auditor:ignore naming/mixed-caps until=2999-12-31 generated binding
+var snake_case = 1
+`, "naming")
+
+	if len(result.Suppressed) != 1 {
+		t.Fatalf("got %d suppressed violations, want 1", len(result.Suppressed))
+	}
+	got := result.Suppressed[0]
+	if got.Directive != "//auditor:ignore naming/mixed-caps until=2999-12-31 generated binding" {
+		t.Errorf("directive %q", got.Directive)
+	}
+	if got.DirectiveLine != 3 || got.Reason != "generated binding" || got.Expires != "2999-12-31" {
+		t.Errorf("got line %d, reason %q, expires %q; want 3, \"generated binding\", \"2999-12-31\"",
+			got.DirectiveLine, got.Reason, got.Expires)
+	}
+}
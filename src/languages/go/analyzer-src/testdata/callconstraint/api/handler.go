@@ -0,0 +1,11 @@
+package api
+
+import "example.com/shop/payments"
+
+// Checkout is the one place allowed to charge customers
+func Checkout(gateway *payments.Gateway, id string) error {
+	if err := payments.ChargeCustomer(id); err != nil {
+		return err
+	}
+	return gateway.ChargeCustomer(id)
+}
@@ -0,0 +1,19 @@
+package payments
+
+// Gateway charges customers through the payment provider
+type Gateway struct{}
+
+// ChargeCustomer charges a customer through the gateway
+func (g *Gateway) ChargeCustomer(id string) error {
+	return nil
+}
+
+// ChargeCustomer charges a customer with the default gateway
+func ChargeCustomer(id string) error {
+	return (&Gateway{}).ChargeCustomer(id)
+}
+
+// Retry charges a customer again; calls inside the package are allowed
+func Retry(id string) error {
+	return ChargeCustomer(id)
+}
@@ -0,0 +1,18 @@
+package web
+
+import pay "example.com/shop/payments"
+
+// Cart charges customers directly, bypassing the api package
+type Cart struct {
+	gateway *pay.Gateway
+}
+
+// Pay calls the gateway method through the receiver's field
+func (c *Cart) Pay(id string) error {
+	return c.gateway.ChargeCustomer(id)
+}
+
+// PayNow calls the package function through an aliased import
+func PayNow(id string) error {
+	return pay.ChargeCustomer(id)
+}
@@ -0,0 +1,14 @@
+module example.com/app
+
+go 1.19
+
+require (
+	example.com/lib v0.0.0
+	example.com/remote v1.0.0
+	example.com/vendored v1.0.0
+)
+
+replace example.com/vendored v1.0.0 => ../vendored
+
+// Replacements with module paths are downloaded, not local
+replace example.com/remote => example.com/fork v1.2.0
@@ -0,0 +1,5 @@
+module example.com/outside
+
+go 1.19
+
+require example.com/lib v0.0.0
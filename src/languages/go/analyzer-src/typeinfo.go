@@ -0,0 +1,98 @@
+package analyzer
+
+import (
+	"go/ast"
+	"go/types"
+	"path/filepath"
+	"sort"
+)
+
+// PackageTypes holds go/types information for one parsed package
+type PackageTypes struct {
+	Dir    string
+	Name   string
+	Pkg    *types.Package
+	Info   *types.Info
+	Errors []string
+}
+
+// Complete reports whether the package type-checked without errors
+func (t *PackageTypes) Complete() bool {
+	return t != nil && t.Pkg != nil && len(t.Errors) == 0
+}
+
+// packageKey identifies a package by directory and package clause name
+type packageKey struct {
+	Dir  string
+	Name string
+}
+
+// packageKeyFor returns the package key of a parsed file
+func packageKeyFor(filePath string, file *ast.File) packageKey {
+	return packageKey{Dir: filepath.Dir(filePath), Name: file.Name.Name}
+}
+
+// packageFiles groups the parsed files by package, with sorted file paths
+func (p *Parser) packageFiles() map[packageKey][]string {
+	groups := make(map[packageKey][]string)
+	for filePath, file := range p.files {
+		key := packageKeyFor(filePath, file)
+		groups[key] = append(groups[key], filePath)
+	}
+	for key := range groups {
+		sort.Strings(groups[key])
+	}
+	return groups
+}
+
+// TypeInfo returns go/types information for the package containing filePath.
+// Packages are type-checked lazily and cached; nil is returned when type
+// checking is disabled or the file is unknown.
+func (p *Parser) TypeInfo(filePath string) *PackageTypes {
+	if p.options.SkipTypeCheck {
+		return nil
+	}
+	file, ok := p.files[filePath]
+	if !ok {
+		return nil
+	}
+
+	key := packageKeyFor(filePath, file)
+	if cached, ok := p.typeInfo[key]; ok {
+		return cached
+	}
+
+	var astFiles []*ast.File
+	for _, path := range p.packageFiles()[key] {
+		astFiles = append(astFiles, p.files[path])
+	}
+
+	result := &PackageTypes{
+		Dir:  key.Dir,
+		Name: key.Name,
+		Info: &types.Info{
+			Types:      make(map[ast.Expr]types.TypeAndValue),
+			Defs:       make(map[*ast.Ident]types.Object),
+			Uses:       make(map[*ast.Ident]types.Object),
+			Implicits:  make(map[ast.Node]types.Object),
+			Selections: make(map[*ast.SelectorExpr]*types.Selection),
+		},
+	}
+
+	if p.importer == nil {
+		p.importer = newLocalImporter(p)
+	}
+	config := types.Config{
+		Importer: p.importer,
+		Error: func(err error) {
+			result.Errors = append(result.Errors, err.Error())
+		},
+	}
+
+	// Errors are collected above; the partially checked package is still useful
+	importPath := p.ImportPathFor(filePath)
+	result.Pkg, _ = config.Check(importPath, p.fileSet, astFiles, result.Info)
+
+	p.typeInfo[key] = result
+	return result
+}
@@ -1,12 +1,141 @@
 package analyzer
 
+import "go/token"
+
 // AnalysisOptions represents options for the analysis
 type AnalysisOptions struct {
-	Analyzers   []string `json:"analyzers"`
-	MinSeverity string   `json:"minSeverity"`
-	Timeout     int      `json:"timeout"`
-	Language    string   `json:"language"`
-	Verbose     bool     `json:"verbose"`
+	Analyzers          []string `json:"analyzers"`
+	MinSeverity        string   `json:"minSeverity"`
+	Timeout            int      `json:"timeout"`
+	Language           string   `json:"language"`
+	Verbose            bool     `json:"verbose"`
+	SkipTypeCheck      bool     `json:"skipTypeCheck"`      // Disables go/types checking for lower latency
+	ContextLinesBefore int      `json:"contextLinesBefore"` // Source lines shown before a violation snippet
+	ContextLinesAfter  int      `json:"contextLinesAfter"`  // Source lines shown after a violation snippet
+
+	// ColumnEncoding selects how columns are counted: "utf-8" (bytes, default) or "utf-16" (LSP)
+	ColumnEncoding string `json:"columnEncoding,omitempty"`
+
+	// Overlay maps file paths to in-memory contents that replace the on-disk files
+	Overlay map[string]string `json:"overlay,omitempty"`
+
+	// Layers declares the architectural layers from highest to lowest
+	Layers []Layer `json:"layers,omitempty"`
+
+	// Rules are the invariant rules from .codeauditor.json; RootDir anchors
+	// their relative path globs (defaults to each file's module root)
+	Rules   []Rule `json:"rules,omitempty"`
+	RootDir string `json:"rootDir,omitempty"`
+
+	// Naming adds project naming conventions on top of the Go idiom checks
+	Naming []NamingConvention `json:"naming,omitempty"`
+
+	// Baseline is the path of a baseline file; findings recorded in it are
+	// not reported again
+	Baseline string `json:"baseline,omitempty"`
+
+	// Settings tune analyzers, keyed by "analyzer" or "analyzer/category";
+	// Overrides replace them for files or packages matching a path glob
+	Settings  map[string]AnalyzerSettings `json:"settings,omitempty"`
+	Overrides []SettingsOverride          `json:"overrides,omitempty"`
+
+	// CacheDir stores per-file results keyed by content, analyzer build and
+	// configuration so unchanged files are not analyzed again
+	CacheDir string `json:"cacheDir,omitempty"`
+
+	// Diff is a unified diff, such as git diff output, and Changes pairs base
+	// and head versions of files; when either is set only violations on
+	// changed head lines are reported, though whole packages are analyzed.
+	// Diff paths are resolved against RootDir, or matched as path suffixes
+	Diff    string       `json:"diff,omitempty"`
+	Changes []FileChange `json:"changes,omitempty"`
+}
+
+// FileChange pairs the base and head versions of a changed file
+type FileChange struct {
+	Base string `json:"base,omitempty"` // Path of the base version; empty for an added file
+	Head string `json:"head"`           // Path of the head version, as analyzed
+}
+
+// AnalyzerSettings enables or disables findings, overrides their severity and
+// sets the thresholds they are reported above
+type AnalyzerSettings struct {
+	Enabled    *bool          `json:"enabled,omitempty"`
+	Severity   string         `json:"severity,omitempty"`
+	Thresholds map[string]int `json:"thresholds,omitempty"`
+}
+
+// SettingsOverride applies settings to the files or packages matching Path
+type SettingsOverride struct {
+	Path     string                      `json:"path"`
+	Settings map[string]AnalyzerSettings `json:"settings"`
+}
+
+// EffectiveSettings echoes the configuration an analysis ran with: the
+// settings of each analyzer and category, thresholds included, and the
+// path overrides applied on top of them
+type EffectiveSettings struct {
+	Settings  map[string]AnalyzerSettings `json:"settings"`
+	Overrides []SettingsOverride          `json:"overrides,omitempty"`
+}
+
+// NamingConvention requires names of one entity type in matching packages or
+// files to match a regular expression
+type NamingConvention struct {
+	Path       string `json:"path,omitempty"`       // File or package glob, all when empty
+	Entity     string `json:"entity,omitempty"`     // function, method, struct, interface, type, const or var; all when empty
+	Visibility string `json:"visibility,omitempty"` // "exported" or "unexported"; both when empty
+	Pattern    string `json:"pattern"`
+	Severity   string `json:"severity,omitempty"`
+	Message    string `json:"message,omitempty"`
+}
+
+// Rule is an invariant rule shared with the TypeScript rules engine; Kind
+// selects which of the kind-specific fields apply
+type Rule struct {
+	ID       string `json:"id"`
+	Kind     string `json:"kind"`
+	Severity string `json:"severity"`
+	Message  string `json:"message,omitempty"`
+	Language string `json:"language,omitempty"`
+
+	// module-boundary: importers matching From may not import targets matching To
+	From   string   `json:"from,omitempty"`
+	To     string   `json:"to,omitempty"`
+	Except []string `json:"except,omitempty"` // Also the allow-list of import-ban
+
+	// import-ban: no file may import Module or a package below it; Replacement
+	// names the package to use instead
+	Module      string `json:"module,omitempty"`
+	Replacement string `json:"replacement,omitempty"`
+
+	// call-constraint: Callee ("[package glob#][Type.]Name") may only be called
+	// from files or packages matching AllowFrom and never from DenyFrom
+	Callee    string   `json:"callee,omitempty"`
+	AllowFrom []string `json:"allowFrom,omitempty"`
+	DenyFrom  []string `json:"denyFrom,omitempty"`
+
+	// naming: exported names in files or packages matching Path must match Exports
+	Path    string `json:"path,omitempty"`
+	Exports string `json:"exports,omitempty"`
+
+	// ast-pattern (language "go"): code matching Pattern in files matching Path
+	// is reported; Constraints restrict what the pattern's metavariables bind
+	Pattern     string                            `json:"pattern,omitempty"`
+	Constraints map[string]MetavariableConstraint `json:"constraints,omitempty"`
+}
+
+// MetavariableConstraint restricts the code an ast-pattern metavariable may bind
+type MetavariableConstraint struct {
+	Type       string `json:"type,omitempty"`       // Exact type, e.g. "error" or "*sql.DB"
+	Implements string `json:"implements,omitempty"` // Interface the type must implement, e.g. "fmt.Stringer"
+	Regex      string `json:"regex,omitempty"`      // Expression the bound source text must match
+}
+
+// Layer is a named group of packages in the declared layer ordering
+type Layer struct {
+	Name     string   `json:"name"`
+	Packages []string `json:"packages"` // Import path globs
 }
 
 // AnalysisResult represents the result of code analysis
@@ -15,6 +144,42 @@ type AnalysisResult struct {
 	IndexEntries []IndexEntry `json:"indexEntries"`
 	Metrics      Metrics      `json:"metrics"`
 	Errors       []Error      `json:"errors"`
+
+	// Suppressed lists the violations silenced by //auditor:ignore directives
+	Suppressed []SuppressedViolation `json:"suppressed"`
+
+	// FixedSinceBaseline lists baseline findings in the analyzed files that no longer occur
+	FixedSinceBaseline []BaselineEntry `json:"fixedSinceBaseline,omitempty"`
+
+	// Settings is the effective analyzer configuration
+	Settings EffectiveSettings `json:"settings"`
+}
+
+// Baseline records the fingerprints of accepted findings
+type Baseline struct {
+	Version      int             `json:"version"`
+	Fingerprints []BaselineEntry `json:"fingerprints"`
+}
+
+// BaselineEntry is one accepted finding; everything but the fingerprint is
+// kept for reporting
+type BaselineEntry struct {
+	Fingerprint string `json:"fingerprint"`
+	File        string `json:"file"` // Relative to the root directory or module root
+	Line        int    `json:"line"`
+	Analyzer    string `json:"analyzer"`
+	Category    string `json:"category"`
+	Severity    string `json:"severity"`
+	Message     string `json:"message"`
+}
+
+// SuppressedViolation is a violation silenced by an inline directive
+type SuppressedViolation struct {
+	Violation
+	Reason        string `json:"reason,omitempty"`
+	Directive     string `json:"directive"`
+	DirectiveLine int    `json:"directiveLine"`
+	Expires       string `json:"expires,omitempty"` // YYYY-MM-DD, when the directive has an expiry date
 }
 
 // Violation represents a code quality violation
@@ -22,6 +187,8 @@ type Violation struct {
 	File        string                 `json:"file"`
 	Line        int                    `json:"line"`
 	Column      int                    `json:"column"`
+	EndLine     int                    `json:"endLine"`
+	EndColumn   int                    `json:"endColumn"`
 	Severity    string                 `json:"severity"`
 	Message     string                 `json:"message"`
 	Details     map[string]interface{} `json:"details,omitempty"`
@@ -29,6 +196,60 @@ type Violation struct {
 	Suggestion  string                 `json:"suggestion,omitempty"`
 	Analyzer    string                 `json:"analyzer"`
 	Category    string                 `json:"category"`
+	Fingerprint string                 `json:"fingerprint,omitempty"` // Stable across line shifts, for baselines
+	Fixes       []Fix                  `json:"fixes,omitempty"`
+}
+
+// Fix is a labeled, mechanical resolution of a violation
+type Fix struct {
+	Description string     `json:"description"`
+	Edits       []TextEdit `json:"edits"`
+}
+
+// TextEdit replaces the bytes [Offset, End) of File with NewText; Hash is
+// the SHA-256 of the content the offsets refer to
+type TextEdit struct {
+	File    string `json:"file"`
+	Offset  int    `json:"offset"`
+	End     int    `json:"end"`
+	NewText string `json:"newText"`
+	Hash    string `json:"hash,omitempty"`
+}
+
+// ApplyFixesRequest selects the fixes to apply: those of Violations, or of
+// the violations with the given Fingerprints found by analyzing Files
+type ApplyFixesRequest struct {
+	Violations   []Violation     `json:"violations,omitempty"`
+	Fingerprints []string        `json:"fingerprints,omitempty"`
+	Files        []string        `json:"files,omitempty"`
+	Options      AnalysisOptions `json:"options"`
+	DryRun       bool            `json:"dryRun"`
+}
+
+// ApplyFixesResult represents the outcome of applying fixes
+type ApplyFixesResult struct {
+	Files   []FixedFile  `json:"files"`
+	Applied []FixOutcome `json:"applied"`
+	Skipped []FixOutcome `json:"skipped"`
+	Errors  []Error      `json:"errors"`
+}
+
+// FixedFile is the formatted result of applying fixes to one file
+type FixedFile struct {
+	File    string `json:"file"`
+	Hash    string `json:"hash"`              // SHA-256 of the new content
+	Diff    string `json:"diff,omitempty"`    // Unified diff, in dry-run mode
+	Content string `json:"content,omitempty"` // New content, for overlay files and in dry-run mode
+	Written bool   `json:"written"`
+}
+
+// FixOutcome identifies an applied or skipped fix
+type FixOutcome struct {
+	Description string `json:"description"`
+	Fingerprint string `json:"fingerprint,omitempty"`
+	File        string `json:"file"`
+	Line        int    `json:"line"`
+	Reason      string `json:"reason,omitempty"` // Why the fix was skipped
 }
 
 // IndexEntry represents an entity in the code index
@@ -57,8 +278,19 @@ type Parameter struct {
 
 // Metrics represents analysis metrics
 type Metrics struct {
-	FilesAnalyzed int64 `json:"filesAnalyzed"`
-	ExecutionTime int64 `json:"executionTime"`
+	FilesAnalyzed    int64 `json:"filesAnalyzed"`
+	PackagesAnalyzed int64 `json:"packagesAnalyzed"`
+	ModulesAnalyzed  int64 `json:"modulesAnalyzed"`
+	ExecutionTime    int64 `json:"executionTime"`
+	CacheHits        int64 `json:"cacheHits"`   // Files whose results came from the cache
+	CacheMisses      int64 `json:"cacheMisses"` // Files analyzed and stored in the cache
+}
+
+// CachePruneResult reports what pruning a cache directory removed
+type CachePruneResult struct {
+	Removed      int   `json:"removed"`
+	RemovedBytes int64 `json:"removedBytes"`
+	Kept         int   `json:"kept"`
 }
 
 // Error represents an analysis error
@@ -71,18 +303,24 @@ type Error struct {
 
 // EntityInfo represents information about a Go entity
 type EntityInfo struct {
-	Name       string
-	Type       string
-	File       string
-	StartLine  int
-	EndLine    int
-	Signature  string
-	Parameters []Parameter
-	ReturnType string
-	Purpose    string
-	Context    string
-	Receiver   string // For methods
-	Package    string
+	Name         string
+	Type         string
+	File         string
+	StartLine    int
+	EndLine      int
+	Signature    string
+	Parameters   []Parameter
+	ReturnType   string
+	Purpose      string
+	Context      string
+	Receiver     string // For methods
+	ReceiverName string // Receiver variable name, for methods
+	Package      string
+	ImportPath   string // Module-qualified package path
+	Module       string // Path of the enclosing module, if any
+
+	namePos token.Pos // Start of the declared name, for violation ranges
+	nameEnd token.Pos
 }
 
 // Function represents a Go function
@@ -97,16 +335,22 @@ type Function struct {
 // Struct represents a Go struct
 type Struct struct {
 	EntityInfo
-	Fields     []Field
-	Methods    []string
-	IsExported bool
+	Fields           []Field
+	Methods          []string
+	PromotedMethods  []string
+	Embeds           []string
+	Implements       []string
+	ImplementsSource string // "types" or "methodset"
+	IsExported       bool
 }
 
 // Interface represents a Go interface
 type Interface struct {
 	EntityInfo
-	Methods    []Method
-	IsExported bool
+	Methods       []Method
+	Embeds        []string
+	ImplementedBy []string
+	IsExported    bool
 }
 
 // Field represents a struct field
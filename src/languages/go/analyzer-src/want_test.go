@@ -0,0 +1,214 @@
+package analyzer
+
+import (
+	"fmt"
+	"go/parser"
+	"go/token"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+// annotatedSamples are the sample projects whose diagnostics are spelled out
+// with // want comments
+var annotatedSamples = []string{"go-advanced"}
+
+// wantOptions are goldenOptions with the function responsibility threshold
+// of solid_violations.go lowered, so MegaProcessor, which counts three
+// responsibilities, is reported
+var wantOptions = AnalysisOptions{
+	Analyzers: goldenOptions.Analyzers,
+	Overrides: []SettingsOverride{{
+		Path: "**/solid_violations.go",
+		Settings: map[string]AnalyzerSettings{
+			"solid/single-responsibility": {Thresholds: map[string]int{"functionResponsibilities": 2}},
+		},
+	}},
+}
+
+// knownFailure is a want annotation of an intended bug that no analyzer
+// reports yet, identified by the sample file and the pattern text
+type knownFailure struct {
+	file    string
+	pattern string
+	reason  string
+}
+
+// knownFailures are logged instead of failing the test; once one of them is
+// reported the test fails until it is removed from the list
+var knownFailures = []knownFailure{
+	{"concurrency_patterns.go", "goroutines/concurrency: (?i).*leak", "no check for goroutines blocked on a channel that is never written or closed"},
+	{"concurrency_patterns.go", "goroutines/concurrency: (?i).*race", "no check for unsynchronized writes to shared variables from goroutines"},
+	{"concurrency_patterns.go", "channels/concurrency: (?i).*deadlock", "no check for goroutines sending to each other on unbuffered channels"},
+	{"concurrency_patterns.go", "goroutines/concurrency: (?i).*unbounded", "no check for one goroutine per loop iteration without a limit"},
+}
+
+// knownFalsePositive is a diagnostic reported for correct sample code,
+// identified by the sample file, "analyzer/category" and entity
+type knownFalsePositive struct {
+	file   string
+	rule   string
+	entity string
+	reason string
+}
+
+// knownFalsePositives are logged instead of failing the test; once one of
+// them is no longer reported the test fails until it is removed from the list
+var knownFalsePositives = []knownFalsePositive{}
+
+// knownFalsePositiveOf returns the known false positive covering a
+// violation, if any
+func knownFalsePositiveOf(violation Violation) *knownFalsePositive {
+	for i, known := range knownFalsePositives {
+		if known.file == filepath.Base(violation.File) && known.rule == violation.Analyzer+"/"+violation.Category &&
+			known.entity == violationEntity(violation) {
+			return &knownFalsePositives[i]
+		}
+	}
+	return nil
+}
+
+// violationEntity returns the name of the declaration a violation is about
+func violationEntity(violation Violation) string {
+	for _, key := range []string{"struct", "interface", "function", "name"} {
+		if name, ok := violation.Details[key].(string); ok {
+			return name
+		}
+	}
+	return ""
+}
+
+// knownFailureOf returns the known failure covering want, if any
+func knownFailureOf(want *wantPattern) *knownFailure {
+	for i, known := range knownFailures {
+		if known.file == filepath.Base(want.file) && known.pattern == want.pattern.String() {
+			return &knownFailures[i]
+		}
+	}
+	return nil
+}
+
+// wantPattern is one diagnostic expected by a // want comment
+type wantPattern struct {
+	file    string
+	line    int
+	pattern *regexp.Regexp
+	matched bool
+}
+
+// parseWants collects the // want "regexp" annotations of a Go file. Each
+// quoted pattern expects one diagnostic on the comment's line whose
+// "analyzer/category: message" text it matches.
+func parseWants(filePath string) ([]*wantPattern, error) {
+	fileSet := token.NewFileSet()
+	file, err := parser.ParseFile(fileSet, filePath, nil, parser.ParseComments)
+	if err != nil {
+		return nil, err
+	}
+
+	var wants []*wantPattern
+	for _, group := range file.Comments {
+		for _, comment := range group.List {
+			text := strings.TrimSpace(strings.TrimPrefix(comment.Text, "//"))
+			if !strings.HasPrefix(text, "want ") {
+				continue
+			}
+			line := fileSet.Position(comment.Pos()).Line
+
+			rest := strings.TrimSpace(strings.TrimPrefix(text, "want "))
+			for rest != "" {
+				quoted, err := strconv.QuotedPrefix(rest)
+				if err != nil {
+					return nil, fmt.Errorf("%s:%d: malformed want comment: %v", filePath, line, err)
+				}
+				unquoted, _ := strconv.Unquote(quoted)
+				pattern, err := regexp.Compile(unquoted)
+				if err != nil {
+					return nil, fmt.Errorf("%s:%d: invalid want pattern: %v", filePath, line, err)
+				}
+				wants = append(wants, &wantPattern{file: filePath, line: line, pattern: pattern})
+				rest = strings.TrimSpace(rest[len(quoted):])
+			}
+		}
+	}
+	return wants, nil
+}
+
+// diagnosticText is the text a want pattern is matched against
+func diagnosticText(violation Violation) string {
+	return violation.Analyzer + "/" + violation.Category + ": " + violation.Message
+}
+
+// TestWantAnnotations checks that the analyzers report exactly the
+// diagnostics annotated in each sample: every diagnostic must match a want
+// pattern on its line or be listed in knownFalsePositives, and every pattern
+// not listed in knownFailures must be matched once
+func TestWantAnnotations(t *testing.T) {
+	for _, sample := range annotatedSamples {
+		sample := sample
+		t.Run(sample, func(t *testing.T) {
+			files, err := filepath.Glob(filepath.Join(samplesDir, sample, "*.go"))
+			if err != nil || len(files) == 0 {
+				t.Fatalf("no Go files in sample %s: %v", sample, err)
+			}
+
+			var wants []*wantPattern
+			for _, filePath := range files {
+				fileWants, err := parseWants(filePath)
+				if err != nil {
+					t.Fatal(err)
+				}
+				wants = append(wants, fileWants...)
+			}
+
+			result, err := NewAnalyzer(wantOptions).Analyze(files)
+			if err != nil {
+				t.Fatalf("analysis failed: %v", err)
+			}
+
+			matched := 0
+			reported := make(map[*knownFalsePositive]bool)
+			for _, violation := range result.Violations {
+				text := diagnosticText(violation)
+				found := false
+				for _, want := range wants {
+					if !want.matched && want.file == violation.File && want.line == violation.Line && want.pattern.MatchString(text) {
+						want.matched, found = true, true
+						matched++
+						break
+					}
+				}
+				if found {
+					continue
+				}
+				if known := knownFalsePositiveOf(violation); known != nil {
+					t.Logf("%s:%d: known false positive %s: %s", violation.File, violation.Line, text, known.reason)
+					reported[known] = true
+					continue
+				}
+				t.Errorf("%s:%d: unexpected diagnostic: %s", violation.File, violation.Line, text)
+			}
+			for i, known := range knownFalsePositives {
+				if !reported[&knownFalsePositives[i]] {
+					t.Errorf("%s: known false positive %s on %s is no longer reported; remove it from knownFalsePositives", known.file, known.rule, known.entity)
+				}
+			}
+			for _, want := range wants {
+				known := knownFailureOf(want)
+				switch {
+				case !want.matched && known != nil:
+					t.Logf("%s:%d: known failure %q: %s", want.file, want.line, want.pattern, known.reason)
+				case !want.matched:
+					t.Errorf("%s:%d: no diagnostic matching %q", want.file, want.line, want.pattern)
+				case known != nil:
+					t.Errorf("%s:%d: known failure %q is now reported; remove it from knownFailures", want.file, want.line, want.pattern)
+				}
+			}
+
+			t.Logf("%d of %d diagnostics expected, %d of %d expectations met",
+				matched, len(result.Violations), matched, len(wants))
+		})
+	}
+}
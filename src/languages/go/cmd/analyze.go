@@ -2,60 +2,277 @@ package main
 
 import (
 	"encoding/json"
+	"flag"
 	"fmt"
+	"io"
 	"os"
 	"strings"
+	"time"
 
 	"code-auditor-go/analyzer"
 )
 
+// Exit codes: a failed quality gate is told apart from a failed run
+const (
+	exitGateFailed = 1
+	exitError      = 2
+)
+
 func main() {
-	if len(os.Args) < 2 {
-		fmt.Fprintf(os.Stderr, "Usage: %s <options-json> <file1> [file2] ...\n", os.Args[0])
-		os.Exit(1)
+	if len(os.Args) > 1 {
+		switch os.Args[1] {
+		case "baseline":
+			writeBaseline(os.Args[2:])
+			return
+		case "apply-fixes":
+			applyFixes(os.Args[2:])
+			return
+		case "cache-prune":
+			pruneCache(os.Args[2:])
+			return
+		}
+	}
+	os.Exit(analyze(os.Args[1:]))
+}
+
+// analyze runs the analysis of the given flags and paths, writes the report
+// and returns the exit code of the quality gate
+func analyze(args []string) int {
+	flags := flag.NewFlagSet("analyze", flag.ExitOnError)
+	var analysis analysisFlags
+	analysis.register(flags)
+	format := flags.String("format", "json", "report format: "+strings.Join(reportFormatNames(), ", "))
+	output := flags.String("output", "", "write the report to this file instead of standard output")
+	failOn := flags.String("fail-on", "", "exit with status 1 when a violation is at least this severe: warning or critical")
+	var thresholds gateThresholds
+	flags.Var(intFlag{&thresholds.MaxViolations}, "max-violations", "exit with status 1 when there are more violations than this")
+	flags.Var(intFlag{&thresholds.MaxCritical}, "max-critical", "exit with status 1 when there are more critical violations than this")
+	flags.Var(intFlag{&thresholds.MaxWarnings}, "max-warnings", "exit with status 1 when there are more warnings than this")
+	flags.Var(intFlag{&thresholds.MaxSuggestions}, "max-suggestions", "exit with status 1 when there are more suggestions than this")
+	flags.Usage = usage(flags)
+	flags.Parse(args)
+
+	writeReport, ok := reportFormats[*format]
+	if !ok {
+		fmt.Fprintf(os.Stderr, "Unknown format %q; expected one of %s\n", *format, strings.Join(reportFormatNames(), ", "))
+		return exitError
+	}
+	if *failOn != "" && *failOn != "warning" && *failOn != "critical" {
+		fmt.Fprintf(os.Stderr, "fail-on must be warning or critical\n")
+		return exitError
+	}
+
+	options, config, err := analysis.load()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error reading configuration: %v\n", err)
+		return exitError
+	}
+	if *format == "sarif" {
+		options.ColumnEncoding = analyzer.ColumnEncodingUTF16 // The unit of sarifColumnKind
+	}
+	files, err := goFiles(pathArgs(flags))
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error listing files: %v\n", err)
+		return exitError
+	}
+
+	// Create and run analyzer
+	goAnalyzer := analyzer.NewAnalyzer(options)
+	result, err := goAnalyzer.Analyze(files)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Analysis error: %v\n", err)
+		return exitError
 	}
 
-	// Parse options from JSON
-	var options analyzer.AnalysisOptions
-	if err := json.Unmarshal([]byte(os.Args[1]), &options); err != nil {
-		fmt.Fprintf(os.Stderr, "Error parsing options: %v\n", err)
-		os.Exit(1)
+	if err := writeOutput(*output, writeReport, result); err != nil {
+		fmt.Fprintf(os.Stderr, "Error writing report: %v\n", err)
+		return exitError
 	}
 
-	// Get file list
-	files := os.Args[2:]
-	if len(files) == 0 {
-		fmt.Fprintf(os.Stderr, "No files provided for analysis\n")
-		os.Exit(1)
+	// A misconfigured run fails even when it found nothing
+	if problems := configErrors(result); len(problems) > 0 {
+		fmt.Fprintf(os.Stderr, "Configuration error: %s\n", strings.Join(problems, "; "))
+		return exitError
 	}
 
-	// Filter Go files
-	var goFiles []string
-	for _, file := range files {
-		if strings.HasSuffix(file, ".go") {
-			goFiles = append(goFiles, file)
+	// Flags take precedence over the configured gate
+	gate := qualityGate{Thresholds: config.Thresholds}
+	if config.FailOnCritical {
+		gate.FailOn = "critical"
+	}
+	if *failOn != "" {
+		gate.FailOn = *failOn
+	}
+	flags.Visit(func(set *flag.Flag) {
+		switch set.Name {
+		case "max-violations":
+			gate.Thresholds.MaxViolations = thresholds.MaxViolations
+		case "max-critical":
+			gate.Thresholds.MaxCritical = thresholds.MaxCritical
+		case "max-warnings":
+			gate.Thresholds.MaxWarnings = thresholds.MaxWarnings
+		case "max-suggestions":
+			gate.Thresholds.MaxSuggestions = thresholds.MaxSuggestions
 		}
+	})
+	if failures := gate.check(result); len(failures) > 0 {
+		fmt.Fprintf(os.Stderr, "Quality gate failed: %s\n", strings.Join(failures, "; "))
+		return exitGateFailed
 	}
+	return 0
+}
 
-	if len(goFiles) == 0 {
-		fmt.Fprintf(os.Stderr, "No Go files found in provided list\n")
-		os.Exit(1)
+// usage prints the command lines of the analyzer and the flags of a command
+func usage(flags *flag.FlagSet) func() {
+	return func() {
+		fmt.Fprintf(os.Stderr, "Usage: %s [flags] [path ...]\n", os.Args[0])
+		fmt.Fprintf(os.Stderr, "       %s baseline [flags] <baseline-file> [path ...]\n", os.Args[0])
+		fmt.Fprintf(os.Stderr, "       %s apply-fixes <request-json | ->\n", os.Args[0])
+		fmt.Fprintf(os.Stderr, "       %s cache-prune <cache-dir> [max-age]\n\n", os.Args[0])
+		fmt.Fprintf(os.Stderr, "Paths are Go files, directories or dir/... patterns (default ./...).\n")
+		fmt.Fprintf(os.Stderr, "Options are read from %s; flags override them.\n", defaultConfigFile)
+		fmt.Fprintf(os.Stderr, "Exit status is %d when the quality gate fails and %d on errors.\n\n", exitGateFailed, exitError)
+		flags.PrintDefaults()
 	}
+}
 
-	// Create and run analyzer
+// pathArgs returns the paths to analyze, ./... when none are given
+func pathArgs(flags *flag.FlagSet) []string {
+	if flags.NArg() == 0 {
+		return []string{"./..."}
+	}
+	return flags.Args()
+}
+
+// writeOutput writes the report to standard output or to a file
+func writeOutput(path string, writeReport reportWriter, result *analyzer.AnalysisResult) error {
+	if path == "" {
+		return writeReport(os.Stdout, result)
+	}
+	file, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	if err := writeReport(file, result); err != nil {
+		file.Close()
+		return err
+	}
+	return file.Close()
+}
+
+// writeBaseline records the current findings of the given paths in a
+// baseline file; later runs with the baseline option report only new ones
+func writeBaseline(args []string) {
+	flags := flag.NewFlagSet("baseline", flag.ExitOnError)
+	var analysis analysisFlags
+	analysis.register(flags)
+	flags.Usage = usage(flags)
+	flags.Parse(args)
+	if flags.NArg() < 1 {
+		flags.Usage()
+		os.Exit(exitError)
+	}
+	baselinePath := flags.Arg(0)
+
+	options, _, err := analysis.load()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error reading configuration: %v\n", err)
+		os.Exit(exitError)
+	}
+	paths := flags.Args()[1:]
+	if len(paths) == 0 {
+		paths = []string{"./..."}
+	}
+	files, err := goFiles(paths)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error listing files: %v\n", err)
+		os.Exit(exitError)
+	}
+
+	// The baseline itself must not filter the findings being recorded
+	options.Baseline = ""
 	goAnalyzer := analyzer.NewAnalyzer(options)
-	result, err := goAnalyzer.Analyze(goFiles)
+	result, err := goAnalyzer.Analyze(files)
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "Analysis error: %v\n", err)
-		os.Exit(1)
+		os.Exit(exitError)
+	}
+	if problems := configErrors(result); len(problems) > 0 {
+		fmt.Fprintf(os.Stderr, "Configuration error: %s\n", strings.Join(problems, "; "))
+		os.Exit(exitError)
+	}
+
+	if err := analyzer.WriteBaseline(baselinePath, goAnalyzer.NewBaseline(result.Violations)); err != nil {
+		fmt.Fprintf(os.Stderr, "Error writing baseline: %v\n", err)
+		os.Exit(exitError)
+	}
+	fmt.Printf("Recorded %d findings in %s\n", len(result.Violations), baselinePath)
+}
+
+// applyFixes applies the fixes selected by an applyFixes request, read from
+// the argument or from standard input when it is "-"
+func applyFixes(args []string) {
+	if len(args) != 1 {
+		fmt.Fprintf(os.Stderr, "Usage: %s apply-fixes <request-json | ->\n", os.Args[0])
+		os.Exit(exitError)
+	}
+
+	requestJSON := []byte(args[0])
+	if args[0] == "-" {
+		var err error
+		if requestJSON, err = io.ReadAll(os.Stdin); err != nil {
+			fmt.Fprintf(os.Stderr, "Error reading request: %v\n", err)
+			os.Exit(exitError)
+		}
+	}
+
+	var request analyzer.ApplyFixesRequest
+	if err := json.Unmarshal(requestJSON, &request); err != nil {
+		fmt.Fprintf(os.Stderr, "Error parsing request: %v\n", err)
+		os.Exit(exitError)
+	}
+
+	result, err := analyzer.ApplyFixes(request)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error applying fixes: %v\n", err)
+		os.Exit(exitError)
+	}
+
+	printJSON(result)
+}
+
+// pruneCache removes cache entries unused for longer than the given age,
+// 30 days by default
+func pruneCache(args []string) {
+	if len(args) < 1 || len(args) > 2 {
+		fmt.Fprintf(os.Stderr, "Usage: %s cache-prune <cache-dir> [max-age]\n", os.Args[0])
+		os.Exit(exitError)
+	}
+
+	maxAge := 30 * 24 * time.Hour
+	if len(args) == 2 {
+		var err error
+		if maxAge, err = time.ParseDuration(args[1]); err != nil {
+			fmt.Fprintf(os.Stderr, "Error parsing max age: %v\n", err)
+			os.Exit(exitError)
+		}
+	}
+
+	result, err := analyzer.PruneCache(args[0], maxAge)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error pruning cache: %v\n", err)
+		os.Exit(exitError)
 	}
+	fmt.Printf("Removed %d cache entries (%d bytes), kept %d\n", result.Removed, result.RemovedBytes, result.Kept)
+}
 
-	// Output result as JSON
+// printJSON writes a result to standard output as indented JSON
+func printJSON(result interface{}) {
 	output, err := json.MarshalIndent(result, "", "  ")
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "Error marshaling result: %v\n", err)
-		os.Exit(1)
+		os.Exit(exitError)
 	}
 
 	fmt.Println(string(output))
-}
\ No newline at end of file
+}
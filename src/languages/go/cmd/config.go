@@ -0,0 +1,145 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"code-auditor-go/analyzer"
+)
+
+// defaultConfigFile is read from the working directory when --config is not set
+const defaultConfigFile = ".codeauditor.json"
+
+// configFile is the part of .codeauditor.json the Go analyzer reads. The
+// invariant rules, minimum severity and quality gate are shared with the
+// TypeScript auditor; Go-only analysis options live under "go".
+type configFile struct {
+	MinSeverity    string                    `json:"minSeverity"`
+	Rules          []analyzer.Rule           `json:"rules"`
+	FailOnCritical bool                      `json:"failOnCritical"`
+	Thresholds     gateThresholds            `json:"thresholds"`
+	Go             *analyzer.AnalysisOptions `json:"go"`
+}
+
+// gateThresholds are the most violations of each severity a run may report
+// before the quality gate fails; nil leaves a severity unbounded
+type gateThresholds struct {
+	MaxViolations  *int `json:"maxViolations,omitempty"`
+	MaxCritical    *int `json:"maxCritical,omitempty"`
+	MaxWarnings    *int `json:"maxWarnings,omitempty"`
+	MaxSuggestions *int `json:"maxSuggestions,omitempty"`
+}
+
+// analysisFlags are the flags shared by every command that runs an analysis
+type analysisFlags struct {
+	config      string
+	analyzers   string
+	minSeverity string
+}
+
+// register adds the analysis flags to a flag set
+func (f *analysisFlags) register(flags *flag.FlagSet) {
+	flags.StringVar(&f.config, "config", "", "path of the .codeauditor.json to read (default ./"+defaultConfigFile+" when present)")
+	flags.StringVar(&f.analyzers, "analyzers", "", "comma-separated analyzers to run (default all): "+strings.Join(analyzerNames(), ", "))
+	flags.StringVar(&f.minSeverity, "min-severity", "", "lowest severity to report: suggestion, warning or critical")
+}
+
+// load reads the configuration file and applies the flags on top of it.
+// Relative paths in the file are resolved against its directory, which is
+// also the root of the invariant rules' path globs.
+func (f *analysisFlags) load() (analyzer.AnalysisOptions, configFile, error) {
+	var config configFile
+
+	path := f.config
+	if path == "" {
+		if _, err := os.Stat(defaultConfigFile); err == nil {
+			path = defaultConfigFile
+		}
+	}
+	if path != "" {
+		content, err := os.ReadFile(path)
+		if err != nil {
+			return analyzer.AnalysisOptions{}, config, err
+		}
+		if err := json.Unmarshal(content, &config); err != nil {
+			return analyzer.AnalysisOptions{}, config, fmt.Errorf("%s: %w", path, err)
+		}
+	}
+
+	var options analyzer.AnalysisOptions
+	if config.Go != nil {
+		options = *config.Go
+	}
+	if options.MinSeverity == "" {
+		options.MinSeverity = config.MinSeverity
+	}
+	if len(options.Rules) == 0 {
+		options.Rules = config.Rules
+	}
+	if path != "" {
+		dir, err := filepath.Abs(filepath.Dir(path))
+		if err != nil {
+			return analyzer.AnalysisOptions{}, config, err
+		}
+		if options.RootDir == "" {
+			options.RootDir = dir
+		}
+		options.RootDir = resolvePath(dir, options.RootDir)
+		options.Baseline = resolvePath(dir, options.Baseline)
+		options.CacheDir = resolvePath(dir, options.CacheDir)
+	}
+
+	if f.analyzers != "" {
+		options.Analyzers = nil
+		for _, name := range strings.Split(f.analyzers, ",") {
+			if name = strings.TrimSpace(name); name != "" {
+				options.Analyzers = append(options.Analyzers, name)
+			}
+		}
+	}
+	if len(options.Analyzers) == 0 {
+		options.Analyzers = analyzerNames()
+	}
+
+	if f.minSeverity != "" {
+		options.MinSeverity = f.minSeverity
+	}
+	if options.MinSeverity != "" && severityRank[options.MinSeverity] == 0 {
+		return options, config, errors.New("min-severity must be suggestion, warning or critical")
+	}
+
+	return options, config, nil
+}
+
+// resolvePath anchors a relative path at dir; empty paths stay empty
+func resolvePath(dir, path string) string {
+	if path == "" || filepath.IsAbs(path) {
+		return path
+	}
+	return filepath.Join(dir, path)
+}
+
+// analyzerNames returns the names of the registered analyzers in order.
+// Fact passes report nothing themselves and are left out; they run when an
+// analyzer requires them.
+func analyzerNames() []string {
+	var names []string
+	for _, check := range analyzer.RegisteredChecks() {
+		if len(check.Categories()) > 0 {
+			names = append(names, check.Name())
+		}
+	}
+	return names
+}
+
+// severityRank orders severities from least to most severe
+var severityRank = map[string]int{
+	"suggestion": 1,
+	"warning":    2,
+	"critical":   3,
+}
@@ -0,0 +1,115 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// writeConfig writes a .codeauditor.json with the given content to a
+// temporary directory and returns its path
+func writeConfig(t *testing.T, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), defaultConfigFile)
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestLoadMergesConfigAndFlags(t *testing.T) {
+	path := writeConfig(t, `{
+	"minSeverity": "suggestion",
+	"rules": [{"id": "shared", "kind": "import-ban"}],
+	"failOnCritical": true,
+	"thresholds": {"maxWarnings": 3},
+	"go": {
+		"analyzers": ["naming", "errors"],
+		"minSeverity": "warning",
+		"baseline": "baseline.json",
+		"cacheDir": "/var/cache/auditor"
+	}
+}`)
+	dir := filepath.Dir(path)
+
+	flags := analysisFlags{config: path}
+	options, config, err := flags.load()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{"naming", "errors"}; !reflect.DeepEqual(options.Analyzers, want) {
+		t.Errorf("analyzers %v, want %v", options.Analyzers, want)
+	}
+	if options.MinSeverity != "warning" {
+		t.Errorf("min severity %q, want the Go setting to win", options.MinSeverity)
+	}
+	if len(options.Rules) != 1 || options.Rules[0].ID != "shared" {
+		t.Errorf("rules %+v, want the shared rules", options.Rules)
+	}
+	if options.RootDir != dir || options.Baseline != filepath.Join(dir, "baseline.json") || options.CacheDir != "/var/cache/auditor" {
+		t.Errorf("root %q, baseline %q, cache %q; want paths resolved against %s", options.RootDir, options.Baseline, options.CacheDir, dir)
+	}
+	if !config.FailOnCritical || config.Thresholds.MaxWarnings == nil || *config.Thresholds.MaxWarnings != 3 {
+		t.Errorf("gate config %+v", config)
+	}
+
+	// Flags take precedence over the file
+	flags = analysisFlags{config: path, analyzers: " solid, ,imports ", minSeverity: "critical"}
+	options, _, err = flags.load()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{"solid", "imports"}; !reflect.DeepEqual(options.Analyzers, want) {
+		t.Errorf("analyzers %v, want %v", options.Analyzers, want)
+	}
+	if options.MinSeverity != "critical" {
+		t.Errorf("min severity %q, want the flag", options.MinSeverity)
+	}
+}
+
+func TestLoadDefaults(t *testing.T) {
+	path := writeConfig(t, `{"minSeverity": "warning"}`)
+
+	options, _, err := (&analysisFlags{config: path}).load()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(options.Analyzers, analyzerNames()) {
+		t.Errorf("analyzers %v, want every analyzer", options.Analyzers)
+	}
+	for _, name := range options.Analyzers {
+		if name == "panics" || name == "closes" || name == "enums" {
+			t.Errorf("fact pass %s runs by default", name)
+		}
+	}
+	if options.MinSeverity != "warning" {
+		t.Errorf("min severity %q, want the shared setting", options.MinSeverity)
+	}
+	if options.RootDir != filepath.Dir(path) {
+		t.Errorf("root %q, want the config directory", options.RootDir)
+	}
+}
+
+func TestLoadErrors(t *testing.T) {
+	malformed := writeConfig(t, `{"go": `)
+	tests := []struct {
+		name  string
+		flags analysisFlags
+		want  string
+	}{
+		{"missing file", analysisFlags{config: filepath.Join(t.TempDir(), "missing.json")}, "no such file"},
+		{"malformed file", analysisFlags{config: malformed}, malformed},
+		{"unknown severity", analysisFlags{config: writeConfig(t, `{}`), minSeverity: "error"}, "min-severity must be"},
+	}
+
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			if _, _, err := test.flags.load(); err == nil || !strings.Contains(err.Error(), test.want) {
+				t.Errorf("got error %v, want one mentioning %q", err, test.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,88 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// goFiles expands the path arguments into the Go files to analyze: a file
+// is taken as is, a directory contributes its Go files and "dir/..." every
+// Go file below dir, skipping vendor, testdata and hidden directories.
+// Other files are ignored, so a pre-commit hook can pass every staged file.
+func goFiles(paths []string) ([]string, error) {
+	seen := make(map[string]bool)
+	var files []string
+	add := func(file string) {
+		file = filepath.Clean(file)
+		if strings.HasSuffix(file, ".go") && !seen[file] {
+			seen[file] = true
+			files = append(files, file)
+		}
+	}
+
+	for _, path := range paths {
+		if root, recursive := recursivePattern(path); recursive {
+			err := filepath.Walk(root, func(walked string, info os.FileInfo, err error) error {
+				if err != nil {
+					return err
+				}
+				if info.IsDir() {
+					if walked != root && skippedDir(info.Name()) {
+						return filepath.SkipDir
+					}
+					return nil
+				}
+				add(walked)
+				return nil
+			})
+			if err != nil {
+				return nil, err
+			}
+			continue
+		}
+
+		info, err := os.Stat(path)
+		if err != nil {
+			// Deleted files are passed by pre-commit hooks too
+			if os.IsNotExist(err) && !strings.HasSuffix(path, ".go") {
+				continue
+			}
+			return nil, err
+		}
+		if !info.IsDir() {
+			add(path)
+			continue
+		}
+		entries, err := os.ReadDir(path)
+		if err != nil {
+			return nil, err
+		}
+		for _, entry := range entries {
+			if !entry.IsDir() {
+				add(filepath.Join(path, entry.Name()))
+			}
+		}
+	}
+
+	sort.Strings(files)
+	return files, nil
+}
+
+// recursivePattern returns the root of a "dir/..." argument
+func recursivePattern(path string) (string, bool) {
+	if path == "..." {
+		return ".", true
+	}
+	if strings.HasSuffix(path, "/...") {
+		return strings.TrimSuffix(path, "/..."), true
+	}
+	return "", false
+}
+
+// skippedDir reports directories the go tool ignores in package patterns
+func skippedDir(name string) bool {
+	return name == "vendor" || name == "testdata" ||
+		strings.HasPrefix(name, ".") || strings.HasPrefix(name, "_")
+}
@@ -0,0 +1,86 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestGoFiles(t *testing.T) {
+	dir := t.TempDir()
+	for _, name := range []string{
+		"main.go",
+		"README.md",
+		"pkg/a.go",
+		"pkg/a_test.go",
+		"pkg/sub/b.go",
+		"pkg/testdata/skipped.go",
+		"pkg/vendor/skipped.go",
+		"pkg/.hidden/skipped.go",
+		"pkg/_build/skipped.go",
+	} {
+		path := filepath.Join(dir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte("package p\n"), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	join := func(names ...string) []string {
+		var paths []string
+		for _, name := range names {
+			paths = append(paths, filepath.Join(dir, filepath.FromSlash(name)))
+		}
+		return paths
+	}
+
+	tests := []struct {
+		name  string
+		paths []string
+		want  []string
+	}{
+		{"directory", join("pkg"), join("pkg/a.go", "pkg/a_test.go")},
+		{"recursive pattern", join("pkg/..."), join("pkg/a.go", "pkg/a_test.go", "pkg/sub/b.go")},
+		{"files and duplicates", join("pkg/sub/b.go", "main.go", "README.md", "pkg/sub/../sub/b.go"), join("main.go", "pkg/sub/b.go")},
+		{"skipped directory named explicitly", join("pkg/testdata/..."), join("pkg/testdata/skipped.go")},
+		{"deleted non-Go file", join("deleted.md", "main.go"), join("main.go")},
+	}
+
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			got, err := goFiles(test.paths)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(got, test.want) {
+				t.Errorf("got %v, want %v", got, test.want)
+			}
+		})
+	}
+
+	if _, err := goFiles(join("deleted.go")); err == nil {
+		t.Error("a missing Go file was ignored")
+	}
+}
+
+func TestRecursivePattern(t *testing.T) {
+	tests := []struct {
+		path      string
+		root      string
+		recursive bool
+	}{
+		{"...", ".", true},
+		{"./...", ".", true},
+		{"pkg/...", "pkg", true},
+		{"pkg", "", false},
+		{"pkg...", "", false},
+	}
+	for _, test := range tests {
+		if root, recursive := recursivePattern(test.path); root != test.root || recursive != test.recursive {
+			t.Errorf("recursivePattern(%q) = %q, %v; want %q, %v", test.path, root, recursive, test.root, test.recursive)
+		}
+	}
+}
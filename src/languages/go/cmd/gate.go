@@ -0,0 +1,100 @@
+package main
+
+import (
+	"fmt"
+	"strconv"
+
+	"code-auditor-go/analyzer"
+)
+
+// qualityGate decides whether a run fails: on any violation at or above
+// FailOn, or on more violations of a severity than its threshold allows
+type qualityGate struct {
+	FailOn     string
+	Thresholds gateThresholds
+}
+
+// check returns a reason for each way the result fails the gate
+func (g qualityGate) check(result *analyzer.AnalysisResult) []string {
+	counts := make(map[string]int)
+	for _, violation := range result.Violations {
+		counts[violation.Severity]++
+	}
+
+	var failures []string
+	if g.FailOn != "" {
+		failing := 0
+		for severity, count := range counts {
+			if severityRank[severity] >= severityRank[g.FailOn] {
+				failing += count
+			}
+		}
+		if failing > 0 {
+			failures = append(failures, fmt.Sprintf("%d violations at or above %s (--fail-on=%s)", failing, g.FailOn, g.FailOn))
+		}
+	}
+
+	limits := []struct {
+		name  string
+		limit *int
+		count int
+	}{
+		{"violations", g.Thresholds.MaxViolations, len(result.Violations)},
+		{"critical violations", g.Thresholds.MaxCritical, counts["critical"]},
+		{"warnings", g.Thresholds.MaxWarnings, counts["warning"]},
+		{"suggestions", g.Thresholds.MaxSuggestions, counts["suggestion"]},
+	}
+	for _, limit := range limits {
+		if limit.limit != nil && limit.count > *limit.limit {
+			failures = append(failures, fmt.Sprintf("%d %s, more than the %d allowed", limit.count, limit.name, *limit.limit))
+		}
+	}
+
+	return failures
+}
+
+// configErrorTypes are the analysis error types caused by the configuration:
+// unknown analyzers, invalid settings, rules or naming patterns, and diffs
+// or baselines that cannot be read
+var configErrorTypes = map[string]bool{
+	"analyzer":    true,
+	"requirement": true,
+	"settings":    true,
+	"rule":        true,
+	"naming":      true,
+	"diff":        true,
+	"baseline":    true,
+}
+
+// configErrors returns the messages of the configuration errors of a
+// result; they fail a run regardless of the quality gate
+func configErrors(result *analyzer.AnalysisResult) []string {
+	var problems []string
+	for _, analysisError := range result.Errors {
+		if configErrorTypes[analysisError.Type] {
+			problems = append(problems, analysisError.Message)
+		}
+	}
+	return problems
+}
+
+// intFlag is an optional integer flag; it stays nil unless set
+type intFlag struct {
+	value **int
+}
+
+func (f intFlag) String() string {
+	if f.value == nil || *f.value == nil {
+		return ""
+	}
+	return fmt.Sprint(**f.value)
+}
+
+func (f intFlag) Set(text string) error {
+	value, err := strconv.Atoi(text)
+	if err != nil || value < 0 {
+		return fmt.Errorf("expected a count, got %q", text)
+	}
+	*f.value = &value
+	return nil
+}
@@ -0,0 +1,105 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+
+	"code-auditor-go/analyzer"
+)
+
+// resultWith returns a result with one violation of each given severity
+func resultWith(severities ...string) *analyzer.AnalysisResult {
+	result := &analyzer.AnalysisResult{}
+	for _, severity := range severities {
+		result.Violations = append(result.Violations, analyzer.Violation{Severity: severity})
+	}
+	return result
+}
+
+// limit returns a pointer to a threshold
+func limit(count int) *int {
+	return &count
+}
+
+func TestQualityGate(t *testing.T) {
+	tests := []struct {
+		name   string
+		gate   qualityGate
+		result *analyzer.AnalysisResult
+		want   []string
+	}{
+		{
+			name:   "no gate",
+			result: resultWith("critical", "warning"),
+		},
+		{
+			name:   "fail on warning counts more severe violations",
+			gate:   qualityGate{FailOn: "warning"},
+			result: resultWith("suggestion", "warning", "critical"),
+			want:   []string{"2 violations at or above warning (--fail-on=warning)"},
+		},
+		{
+			name:   "fail on critical passes warnings",
+			gate:   qualityGate{FailOn: "critical"},
+			result: resultWith("suggestion", "warning"),
+		},
+		{
+			name:   "thresholds allow up to their count",
+			gate:   qualityGate{Thresholds: gateThresholds{MaxWarnings: limit(2), MaxCritical: limit(0)}},
+			result: resultWith("warning", "warning", "suggestion"),
+		},
+		{
+			name: "every exceeded threshold is reported",
+			gate: qualityGate{FailOn: "critical", Thresholds: gateThresholds{
+				MaxViolations:  limit(2),
+				MaxCritical:    limit(0),
+				MaxSuggestions: limit(1),
+			}},
+			result: resultWith("critical", "suggestion", "suggestion"),
+			want: []string{
+				"1 violations at or above critical (--fail-on=critical)",
+				"3 violations, more than the 2 allowed",
+				"1 critical violations, more than the 0 allowed",
+				"2 suggestions, more than the 1 allowed",
+			},
+		},
+	}
+
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			if got := test.gate.check(test.result); !reflect.DeepEqual(got, test.want) {
+				t.Errorf("got %q, want %q", got, test.want)
+			}
+		})
+	}
+}
+
+func TestConfigErrors(t *testing.T) {
+	result := &analyzer.AnalysisResult{Errors: []analyzer.Error{
+		{Type: "analyzer", Message: `Unknown analyzer "solidd"`},
+		{Type: "parse", Message: "syntax error"},
+		{Type: "settings", Message: "unknown severity"},
+		{Type: "cache", Message: "cannot write"},
+	}}
+	want := []string{`Unknown analyzer "solidd"`, "unknown severity"}
+	if got := configErrors(result); !reflect.DeepEqual(got, want) {
+		t.Errorf("got %q, want %q", got, want)
+	}
+}
+
+func TestIntFlag(t *testing.T) {
+	var value *int
+	flag := intFlag{&value}
+	if flag.String() != "" {
+		t.Errorf("unset flag renders %q", flag.String())
+	}
+	if err := flag.Set("4"); err != nil || value == nil || *value != 4 {
+		t.Errorf("Set(4) = %v, value %v", err, value)
+	}
+	for _, text := range []string{"-1", "many"} {
+		if err := flag.Set(text); err == nil {
+			t.Errorf("Set(%q) accepted", text)
+		}
+	}
+}
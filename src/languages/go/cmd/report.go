@@ -0,0 +1,356 @@
+package main
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+
+	"code-auditor-go/analyzer"
+)
+
+// reportWriter renders an analysis result in one output format
+type reportWriter func(w io.Writer, result *analyzer.AnalysisResult) error
+
+// reportFormats are the output formats of an analysis, by --format name
+var reportFormats = map[string]reportWriter{
+	"json":       writeJSON,
+	"sarif":      writeSARIF,
+	"checkstyle": writeCheckstyle,
+	"junit":      writeJUnit,
+	"github":     writeGitHubAnnotations,
+	"markdown":   writeMarkdown,
+	"csv":        writeCSV,
+}
+
+// reportFormatNames returns the output format names in ascending order
+func reportFormatNames() []string {
+	names := make([]string, 0, len(reportFormats))
+	for name := range reportFormats {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// writeJSON writes the result as indented JSON
+func writeJSON(w io.Writer, result *analyzer.AnalysisResult) error {
+	output, err := json.MarshalIndent(result, "", "  ")
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintln(w, string(output))
+	return err
+}
+
+// ruleID names the rule of a violation as "analyzer/category"
+func ruleID(violation analyzer.Violation) string {
+	return violation.Analyzer + "/" + violation.Category
+}
+
+// checkstyleReport is the root of a Checkstyle XML report
+type checkstyleReport struct {
+	XMLName xml.Name         `xml:"checkstyle"`
+	Version string           `xml:"version,attr"`
+	Files   []checkstyleFile `xml:"file"`
+}
+
+type checkstyleFile struct {
+	Name   string            `xml:"name,attr"`
+	Errors []checkstyleError `xml:"error"`
+}
+
+type checkstyleError struct {
+	Line     int    `xml:"line,attr"`
+	Column   int    `xml:"column,attr,omitempty"`
+	Severity string `xml:"severity,attr"`
+	Message  string `xml:"message,attr"`
+	Source   string `xml:"source,attr"`
+}
+
+// writeCheckstyle writes the violations as Checkstyle XML, grouped by file
+func writeCheckstyle(w io.Writer, result *analyzer.AnalysisResult) error {
+	report := checkstyleReport{Version: "4.3"}
+	for _, violation := range result.Violations {
+		if len(report.Files) == 0 || report.Files[len(report.Files)-1].Name != violation.File {
+			report.Files = append(report.Files, checkstyleFile{Name: violation.File})
+		}
+		file := &report.Files[len(report.Files)-1]
+		file.Errors = append(file.Errors, checkstyleError{
+			Line:     violation.Line,
+			Column:   violation.Column,
+			Severity: checkstyleSeverity(violation.Severity),
+			Message:  violation.Message,
+			Source:   ruleID(violation),
+		})
+	}
+	return writeXML(w, report)
+}
+
+// checkstyleSeverity maps a severity onto a Checkstyle severity
+func checkstyleSeverity(severity string) string {
+	switch severity {
+	case "critical":
+		return "error"
+	case "warning":
+		return "warning"
+	default:
+		return "info"
+	}
+}
+
+// junitTestSuites is the root of a JUnit XML report
+type junitTestSuites struct {
+	XMLName  xml.Name         `xml:"testsuites"`
+	Name     string           `xml:"name,attr"`
+	Tests    int              `xml:"tests,attr"`
+	Failures int              `xml:"failures,attr"`
+	Errors   int              `xml:"errors,attr"`
+	Suites   []junitTestSuite `xml:"testsuite"`
+}
+
+type junitTestSuite struct {
+	Name      string          `xml:"name,attr"`
+	Tests     int             `xml:"tests,attr"`
+	Failures  int             `xml:"failures,attr"`
+	Errors    int             `xml:"errors,attr"`
+	TestCases []junitTestCase `xml:"testcase"`
+}
+
+type junitTestCase struct {
+	Name      string        `xml:"name,attr"`
+	ClassName string        `xml:"classname,attr"`
+	Failure   *junitFailure `xml:"failure,omitempty"`
+	Error     *junitFailure `xml:"error,omitempty"`
+}
+
+type junitFailure struct {
+	Message string `xml:"message,attr"`
+	Type    string `xml:"type,attr"`
+	Text    string `xml:",chardata"`
+}
+
+// writeJUnit writes the violations as JUnit XML: one suite per file with a
+// failed test case per violation, and analysis errors as errored cases. A
+// clean run reports one passing case so CI shows the audit ran.
+func writeJUnit(w io.Writer, result *analyzer.AnalysisResult) error {
+	report := junitTestSuites{Name: "code-auditor-go"}
+
+	for _, violation := range result.Violations {
+		if len(report.Suites) == 0 || report.Suites[len(report.Suites)-1].Name != violation.File {
+			report.Suites = append(report.Suites, junitTestSuite{Name: violation.File})
+		}
+		suite := &report.Suites[len(report.Suites)-1]
+
+		text := fmt.Sprintf("%s:%d:%d: %s", violation.File, violation.Line, violation.Column, violation.Message)
+		if violation.Suggestion != "" {
+			text += "\nSuggestion: " + violation.Suggestion
+		}
+		suite.TestCases = append(suite.TestCases, junitTestCase{
+			Name:      fmt.Sprintf("%s:%d:%d", violation.File, violation.Line, violation.Column),
+			ClassName: ruleID(violation),
+			Failure:   &junitFailure{Message: violation.Message, Type: violation.Severity, Text: text},
+		})
+		suite.Tests++
+		suite.Failures++
+	}
+
+	if len(result.Errors) > 0 {
+		suite := junitTestSuite{Name: "analysis"}
+		for _, analysisError := range result.Errors {
+			suite.TestCases = append(suite.TestCases, junitTestCase{
+				Name:      analysisError.Type,
+				ClassName: "analysis",
+				Error:     &junitFailure{Message: analysisError.Message, Type: analysisError.Type, Text: analysisError.Message},
+			})
+			suite.Tests++
+			suite.Errors++
+		}
+		report.Suites = append(report.Suites, suite)
+	}
+
+	if len(report.Suites) == 0 {
+		report.Suites = []junitTestSuite{{
+			Name:      "code-auditor-go",
+			Tests:     1,
+			TestCases: []junitTestCase{{Name: "audit", ClassName: "code-auditor-go"}},
+		}}
+	}
+	for _, suite := range report.Suites {
+		report.Tests += suite.Tests
+		report.Failures += suite.Failures
+		report.Errors += suite.Errors
+	}
+
+	return writeXML(w, report)
+}
+
+// writeXML writes an indented XML document with its header
+func writeXML(w io.Writer, document interface{}) error {
+	output, err := xml.MarshalIndent(document, "", "  ")
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintf(w, "%s%s\n", xml.Header, output)
+	return err
+}
+
+// writeGitHubAnnotations writes one GitHub Actions workflow command per
+// violation and analysis error, so findings appear as annotations on the diff
+func writeGitHubAnnotations(w io.Writer, result *analyzer.AnalysisResult) error {
+	for _, violation := range result.Violations {
+		properties := []string{"file=" + escapeGitHubProperty(violation.File)}
+		if violation.Line > 0 {
+			properties = append(properties, "line="+strconv.Itoa(violation.Line))
+			if violation.EndLine > 0 {
+				properties = append(properties, "endLine="+strconv.Itoa(violation.EndLine))
+			}
+			// Columns only apply to annotations on a single line
+			if violation.Column > 0 && (violation.EndLine == 0 || violation.EndLine == violation.Line) {
+				properties = append(properties, "col="+strconv.Itoa(violation.Column))
+				if violation.EndColumn > 0 {
+					properties = append(properties, "endColumn="+strconv.Itoa(violation.EndColumn))
+				}
+			}
+		}
+		properties = append(properties, "title="+escapeGitHubProperty(ruleID(violation)))
+
+		message := violation.Message
+		if violation.Suggestion != "" {
+			message += "\n" + violation.Suggestion
+		}
+		if _, err := fmt.Fprintf(w, "::%s %s::%s\n", githubLevel(violation.Severity),
+			strings.Join(properties, ","), escapeGitHubData(message)); err != nil {
+			return err
+		}
+	}
+
+	for _, analysisError := range result.Errors {
+		command := "::error"
+		if analysisError.File != "" {
+			command += " file=" + escapeGitHubProperty(analysisError.File)
+			if analysisError.Line > 0 {
+				command += ",line=" + strconv.Itoa(analysisError.Line)
+			}
+		}
+		if _, err := fmt.Fprintf(w, "%s::%s\n", command,
+			escapeGitHubData(analysisError.Type+": "+analysisError.Message)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// githubLevel maps a severity onto a workflow command
+func githubLevel(severity string) string {
+	switch severity {
+	case "critical":
+		return "error"
+	case "warning":
+		return "warning"
+	default:
+		return "notice"
+	}
+}
+
+// escapeGitHubData escapes the message of a workflow command
+func escapeGitHubData(text string) string {
+	return strings.NewReplacer("%", "%25", "\r", "%0D", "\n", "%0A").Replace(text)
+}
+
+// escapeGitHubProperty escapes a property value of a workflow command
+func escapeGitHubProperty(text string) string {
+	return strings.NewReplacer("%", "%25", "\r", "%0D", "\n", "%0A", ":", "%3A", ",", "%2C").Replace(text)
+}
+
+// writeMarkdown writes a summary of the result: counts by severity and by
+// rule, then a table of the violations and any analysis errors
+func writeMarkdown(w io.Writer, result *analyzer.AnalysisResult) error {
+	var builder strings.Builder
+	builder.WriteString("## Code audit\n\n")
+
+	if len(result.Violations) == 0 {
+		fmt.Fprintf(&builder, "No violations in %d files.\n", result.Metrics.FilesAnalyzed)
+	} else {
+		severities := make(map[string]int)
+		rules := make(map[string]int)
+		for _, violation := range result.Violations {
+			severities[violation.Severity]++
+			rules[ruleID(violation)]++
+		}
+		fmt.Fprintf(&builder, "%d violations in %d files (critical: %d, warning: %d, suggestion: %d).\n\n",
+			len(result.Violations), result.Metrics.FilesAnalyzed,
+			severities["critical"], severities["warning"], severities["suggestion"])
+
+		ids := make([]string, 0, len(rules))
+		for id := range rules {
+			ids = append(ids, id)
+		}
+		sort.Strings(ids)
+		builder.WriteString("| Rule | Violations |\n|---|---:|\n")
+		for _, id := range ids {
+			fmt.Fprintf(&builder, "| `%s` | %d |\n", id, rules[id])
+		}
+
+		builder.WriteString("\n| Location | Severity | Rule | Message |\n|---|---|---|---|\n")
+		for _, violation := range result.Violations {
+			fmt.Fprintf(&builder, "| `%s:%d` | %s | `%s` | %s |\n",
+				violation.File, violation.Line, violation.Severity, ruleID(violation), escapeMarkdownCell(violation.Message))
+		}
+	}
+
+	if len(result.Suppressed) > 0 {
+		fmt.Fprintf(&builder, "\n%d violations suppressed in source.\n", len(result.Suppressed))
+	}
+
+	if len(result.Errors) > 0 {
+		builder.WriteString("\n### Analysis errors\n\n")
+		for _, analysisError := range result.Errors {
+			location := ""
+			if analysisError.File != "" {
+				location = fmt.Sprintf(" (`%s`)", analysisError.File)
+			}
+			fmt.Fprintf(&builder, "- **%s**%s: %s\n", analysisError.Type, location, analysisError.Message)
+		}
+	}
+
+	_, err := io.WriteString(w, builder.String())
+	return err
+}
+
+// escapeMarkdownCell keeps text on one table row
+func escapeMarkdownCell(text string) string {
+	return strings.NewReplacer("|", `\|`, "\r", "", "\n", "<br>").Replace(text)
+}
+
+// writeCSV writes one row per violation with a header row
+func writeCSV(w io.Writer, result *analyzer.AnalysisResult) error {
+	writer := csv.NewWriter(w)
+	if err := writer.Write([]string{
+		"file", "line", "column", "endLine", "endColumn", "severity", "analyzer", "category", "message", "fingerprint",
+	}); err != nil {
+		return err
+	}
+	for _, violation := range result.Violations {
+		if err := writer.Write([]string{
+			violation.File,
+			strconv.Itoa(violation.Line),
+			strconv.Itoa(violation.Column),
+			strconv.Itoa(violation.EndLine),
+			strconv.Itoa(violation.EndColumn),
+			violation.Severity,
+			violation.Analyzer,
+			violation.Category,
+			violation.Message,
+			violation.Fingerprint,
+		}); err != nil {
+			return err
+		}
+	}
+	writer.Flush()
+	return writer.Error()
+}
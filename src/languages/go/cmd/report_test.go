@@ -0,0 +1,98 @@
+package main
+
+import (
+	"bytes"
+	"encoding/xml"
+	"strings"
+	"testing"
+
+	"code-auditor-go/analyzer"
+)
+
+// awkwardResult has markup, quotes, newlines and workflow command
+// separators in its paths and messages
+func awkwardResult() *analyzer.AnalysisResult {
+	return &analyzer.AnalysisResult{
+		Violations: []analyzer.Violation{{
+			File:       "pkg/a,b:c.go",
+			Line:       3,
+			Column:     2,
+			EndLine:    3,
+			EndColumn:  9,
+			Severity:   "warning",
+			Message:    `Use <T> & "quotes" at 100%`,
+			Suggestion: "First line\r\nsecond line",
+			Analyzer:   "naming",
+			Category:   "mixed-caps",
+		}},
+		Errors: []analyzer.Error{{Type: "parse", Message: "expected ';', found 'EOF'\nat end", File: "pkg/broken.go", Line: 7}},
+	}
+}
+
+func TestCheckstyleEscaping(t *testing.T) {
+	var output bytes.Buffer
+	if err := writeCheckstyle(&output, awkwardResult()); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(output.String(), `message="Use &lt;T&gt; &amp; &#34;quotes&#34; at 100%"`) {
+		t.Errorf("message is not escaped:\n%s", output.String())
+	}
+
+	var report checkstyleReport
+	if err := xml.Unmarshal(output.Bytes(), &report); err != nil {
+		t.Fatalf("invalid XML: %v\n%s", err, output.String())
+	}
+	if len(report.Files) != 1 || report.Files[0].Name != "pkg/a,b:c.go" || report.Files[0].Errors[0].Message != `Use <T> & "quotes" at 100%` {
+		t.Errorf("round trip lost data: %+v", report)
+	}
+}
+
+func TestJUnitEscaping(t *testing.T) {
+	var output bytes.Buffer
+	if err := writeJUnit(&output, awkwardResult()); err != nil {
+		t.Fatal(err)
+	}
+
+	var report junitTestSuites
+	if err := xml.Unmarshal(output.Bytes(), &report); err != nil {
+		t.Fatalf("invalid XML: %v\n%s", err, output.String())
+	}
+	if report.Tests != 2 || report.Failures != 1 || report.Errors != 1 {
+		t.Errorf("counts: %d tests, %d failures, %d errors", report.Tests, report.Failures, report.Errors)
+	}
+	failure := report.Suites[0].TestCases[0].Failure
+	if failure == nil || failure.Message != `Use <T> & "quotes" at 100%` ||
+		failure.Text != "pkg/a,b:c.go:3:2: Use <T> & \"quotes\" at 100%\nSuggestion: First line\r\nsecond line" {
+		t.Errorf("failure %+v", failure)
+	}
+	if analysisError := report.Suites[1].TestCases[0].Error; analysisError == nil || analysisError.Message != "expected ';', found 'EOF'\nat end" {
+		t.Errorf("error %+v", analysisError)
+	}
+}
+
+func TestJUnitCleanRun(t *testing.T) {
+	var output bytes.Buffer
+	if err := writeJUnit(&output, &analyzer.AnalysisResult{}); err != nil {
+		t.Fatal(err)
+	}
+	var report junitTestSuites
+	if err := xml.Unmarshal(output.Bytes(), &report); err != nil {
+		t.Fatal(err)
+	}
+	if report.Tests != 1 || report.Failures != 0 || len(report.Suites) != 1 {
+		t.Errorf("clean run reported %+v", report)
+	}
+}
+
+func TestGitHubAnnotationEscaping(t *testing.T) {
+	var output bytes.Buffer
+	if err := writeGitHubAnnotations(&output, awkwardResult()); err != nil {
+		t.Fatal(err)
+	}
+	want := "::warning file=pkg/a%2Cb%3Ac.go,line=3,endLine=3,col=2,endColumn=9,title=naming/mixed-caps::" +
+		"Use <T> & \"quotes\" at 100%25%0AFirst line%0D%0Asecond line\n" +
+		"::error file=pkg/broken.go,line=7::parse: expected ';', found 'EOF'%0Aat end\n"
+	if output.String() != want {
+		t.Errorf("got\n%s\nwant\n%s", output.String(), want)
+	}
+}
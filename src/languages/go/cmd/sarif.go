@@ -0,0 +1,295 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"code-auditor-go/analyzer"
+)
+
+// sarifSchema is the JSON schema of SARIF 2.1.0 logs
+const sarifSchema = "https://json.schemastore.org/sarif-2.1.0.json"
+
+// sarifLog is the root of a SARIF 2.1.0 log
+type sarifLog struct {
+	Schema  string     `json:"$schema"`
+	Version string     `json:"version"`
+	Runs    []sarifRun `json:"runs"`
+}
+
+type sarifRun struct {
+	Tool               sarifTool                   `json:"tool"`
+	OriginalURIBaseIDs map[string]sarifArtifactURI `json:"originalUriBaseIds,omitempty"`
+	ColumnKind         string                      `json:"columnKind"`
+	Results            []sarifResult               `json:"results"`
+	Invocations        []sarifInvocation           `json:"invocations"`
+}
+
+type sarifTool struct {
+	Driver sarifDriver `json:"driver"`
+}
+
+type sarifDriver struct {
+	Name           string      `json:"name"`
+	Version        string      `json:"version"`
+	InformationURI string      `json:"informationUri"`
+	Rules          []sarifRule `json:"rules"`
+}
+
+type sarifRule struct {
+	ID                   string             `json:"id"`
+	Name                 string             `json:"name"`
+	ShortDescription     sarifMessage       `json:"shortDescription"`
+	FullDescription      *sarifMessage      `json:"fullDescription,omitempty"`
+	DefaultConfiguration sarifConfiguration `json:"defaultConfiguration"`
+	Properties           sarifRuleTags      `json:"properties"`
+}
+
+type sarifConfiguration struct {
+	Level string `json:"level"`
+}
+
+type sarifRuleTags struct {
+	Tags []string `json:"tags"`
+}
+
+type sarifMessage struct {
+	Text string `json:"text"`
+}
+
+type sarifResult struct {
+	RuleID              string             `json:"ruleId"`
+	RuleIndex           int                `json:"ruleIndex"`
+	Level               string             `json:"level"`
+	Message             sarifMessage       `json:"message"`
+	Locations           []sarifLocation    `json:"locations"`
+	PartialFingerprints map[string]string  `json:"partialFingerprints,omitempty"`
+	Suppressions        []sarifSuppression `json:"suppressions,omitempty"`
+}
+
+type sarifLocation struct {
+	PhysicalLocation sarifPhysicalLocation `json:"physicalLocation"`
+}
+
+type sarifPhysicalLocation struct {
+	ArtifactLocation sarifArtifactURI `json:"artifactLocation"`
+	Region           sarifRegion      `json:"region"`
+}
+
+type sarifArtifactURI struct {
+	URI       string `json:"uri"`
+	URIBaseID string `json:"uriBaseId,omitempty"`
+}
+
+type sarifRegion struct {
+	StartLine   int `json:"startLine"`
+	StartColumn int `json:"startColumn,omitempty"`
+	EndLine     int `json:"endLine,omitempty"`
+	EndColumn   int `json:"endColumn,omitempty"`
+}
+
+type sarifSuppression struct {
+	Kind          string `json:"kind"`
+	Status        string `json:"status"`
+	Justification string `json:"justification,omitempty"`
+}
+
+type sarifInvocation struct {
+	ExecutionSuccessful        bool                `json:"executionSuccessful"`
+	ToolExecutionNotifications []sarifNotification `json:"toolExecutionNotifications,omitempty"`
+}
+
+type sarifNotification struct {
+	Level     string          `json:"level"`
+	Message   sarifMessage    `json:"message"`
+	Locations []sarifLocation `json:"locations,omitempty"`
+}
+
+// sarifFingerprintKey names the analyzer's fingerprint in partialFingerprints
+const sarifFingerprintKey = "codeAuditorFingerprint/v1"
+
+// sarifColumnKind is the column unit of SARIF regions; the analysis must
+// count columns in UTF-16 code units to match it
+const sarifColumnKind = "utf16CodeUnits"
+
+// srcRoot is the URI base id that relative artifact locations resolve against
+const srcRoot = "%SRCROOT%"
+
+// writeSARIF writes the result as a SARIF 2.1.0 log with one rule per
+// analyzer category. Suppressed findings are included with an in-source
+// suppression; paths under the working directory are relative to %SRCROOT%.
+func writeSARIF(w io.Writer, result *analyzer.AnalysisResult) error {
+	root, _ := os.Getwd()
+	output, err := json.MarshalIndent(sarifLogOf(result, root), "", "  ")
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintln(w, string(output))
+	return err
+}
+
+// sarifLogOf converts the result with paths below root relative to %SRCROOT%.
+// The run only counts as successful when the analysis reported no errors.
+func sarifLogOf(result *analyzer.AnalysisResult, root string) sarifLog {
+	findings := make([]analyzer.SuppressedViolation, 0, len(result.Violations)+len(result.Suppressed))
+	for _, violation := range result.Violations {
+		findings = append(findings, analyzer.SuppressedViolation{Violation: violation})
+	}
+	findings = append(findings, result.Suppressed...)
+
+	rules, ruleIndex := sarifRules(findings)
+
+	results := make([]sarifResult, 0, len(findings))
+	for _, finding := range findings {
+		id := ruleID(finding.Violation)
+		sarif := sarifResult{
+			RuleID:    id,
+			RuleIndex: ruleIndex[id],
+			Level:     sarifLevel(finding.Severity),
+			Message:   sarifMessage{Text: finding.Message},
+			Locations: []sarifLocation{sarifLocationOf(root, finding.File, sarifRegionOf(finding.Violation))},
+		}
+		if finding.Fingerprint != "" {
+			sarif.PartialFingerprints = map[string]string{sarifFingerprintKey: finding.Fingerprint}
+		}
+		if finding.Directive != "" {
+			sarif.Suppressions = []sarifSuppression{{
+				Kind:          "inSource",
+				Status:        "accepted",
+				Justification: finding.Reason,
+			}}
+		}
+		results = append(results, sarif)
+	}
+
+	notifications := []sarifNotification{}
+	for _, analysisError := range result.Errors {
+		notification := sarifNotification{
+			Level:   "error",
+			Message: sarifMessage{Text: fmt.Sprintf("%s: %s", analysisError.Type, analysisError.Message)},
+		}
+		if analysisError.File != "" {
+			notification.Locations = []sarifLocation{sarifLocationOf(root, analysisError.File, sarifRegion{StartLine: analysisError.Line})}
+			if analysisError.Line == 0 {
+				notification.Locations[0].PhysicalLocation.Region.StartLine = 1
+			}
+		}
+		notifications = append(notifications, notification)
+	}
+
+	run := sarifRun{
+		Tool: sarifTool{Driver: sarifDriver{
+			Name:           "code-auditor-go",
+			Version:        analyzer.Version,
+			InformationURI: "https://github.com/BenAHammond/code-auditor-mcp",
+			Rules:          rules,
+		}},
+		ColumnKind: sarifColumnKind,
+		Results:    results,
+		Invocations: []sarifInvocation{{
+			ExecutionSuccessful:        len(result.Errors) == 0,
+			ToolExecutionNotifications: notifications,
+		}},
+	}
+	if root != "" {
+		run.OriginalURIBaseIDs = map[string]sarifArtifactURI{srcRoot: {URI: fileURI(root) + "/"}}
+	}
+
+	return sarifLog{Schema: sarifSchema, Version: "2.1.0", Runs: []sarifRun{run}}
+}
+
+// sarifRules returns a rule for each analyzer category with findings, in
+// id order, and the index of each rule id
+func sarifRules(findings []analyzer.SuppressedViolation) ([]sarifRule, map[string]int) {
+	byID := make(map[string]analyzer.Violation)
+	for _, finding := range findings {
+		if _, seen := byID[ruleID(finding.Violation)]; !seen {
+			byID[ruleID(finding.Violation)] = finding.Violation
+		}
+	}
+	ids := make([]string, 0, len(byID))
+	for id := range byID {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+
+	rules := make([]sarifRule, 0, len(ids))
+	ruleIndex := make(map[string]int)
+	for index, id := range ids {
+		violation := byID[id]
+		rule := sarifRule{
+			ID:                   id,
+			Name:                 violation.Category,
+			ShortDescription:     sarifMessage{Text: fmt.Sprintf("%s: %s", violation.Analyzer, violation.Category)},
+			DefaultConfiguration: sarifConfiguration{Level: sarifLevel(violation.Severity)},
+			Properties:           sarifRuleTags{Tags: []string{violation.Analyzer}},
+		}
+		if check, ok := analyzer.LookupCheck(violation.Analyzer); ok {
+			rule.FullDescription = &sarifMessage{Text: check.Description()}
+		}
+		rules = append(rules, rule)
+		ruleIndex[id] = index
+	}
+	return rules, ruleIndex
+}
+
+// sarifRegionOf returns the region of a violation. SARIF end columns are
+// exclusive, so an empty range, such as an insertion point, is widened to
+// the character at its start.
+func sarifRegionOf(violation analyzer.Violation) sarifRegion {
+	region := sarifRegion{
+		StartLine:   violation.Line,
+		StartColumn: violation.Column,
+		EndLine:     violation.EndLine,
+		EndColumn:   violation.EndColumn,
+	}
+	if region.StartLine == 0 {
+		region.StartLine = 1
+	}
+	if region.StartColumn > 0 && region.EndLine == region.StartLine && region.EndColumn <= region.StartColumn {
+		region.EndColumn = region.StartColumn + 1
+	}
+	return region
+}
+
+// sarifLocationOf locates a region of a file, relative to %SRCROOT% when
+// the file is below it
+func sarifLocationOf(root, file string, region sarifRegion) sarifLocation {
+	artifact := sarifArtifactURI{URI: fileURI(file)}
+	if abs, err := filepath.Abs(file); err == nil && root != "" {
+		if relative, err := filepath.Rel(root, abs); err == nil && !strings.HasPrefix(relative, "..") {
+			artifact = sarifArtifactURI{URI: (&url.URL{Path: filepath.ToSlash(relative)}).String(), URIBaseID: srcRoot}
+		}
+	}
+	return sarifLocation{PhysicalLocation: sarifPhysicalLocation{ArtifactLocation: artifact, Region: region}}
+}
+
+// fileURI returns the file URI of a path
+func fileURI(path string) string {
+	if abs, err := filepath.Abs(path); err == nil {
+		path = abs
+	}
+	slashPath := filepath.ToSlash(path)
+	if !strings.HasPrefix(slashPath, "/") {
+		slashPath = "/" + slashPath // Windows drive paths
+	}
+	return (&url.URL{Scheme: "file", Path: slashPath}).String()
+}
+
+// sarifLevel maps a severity onto a SARIF result level
+func sarifLevel(severity string) string {
+	switch severity {
+	case "critical":
+		return "error"
+	case "warning":
+		return "warning"
+	default:
+		return "note"
+	}
+}
@@ -0,0 +1,114 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"code-auditor-go/analyzer"
+)
+
+var update = flag.Bool("update", false, "rewrite the golden files in testdata")
+
+// sarifSource has a naming finding after a multi-byte identifier, so UTF-8
+// and UTF-16 columns differ, and a suppressed one
+const sarifSource = `package report
+
+const π, snake_case = 3.14, 1
+
+//auditor:ignore naming/mixed-caps kept for the wire format
+var wire_name = "x"
+`
+
+// TestSARIFGolden compares the SARIF log of a small module with
+// testdata/report.sarif; run with -update to accept changes
+func TestSARIFGolden(t *testing.T) {
+	root := t.TempDir()
+	if err := os.WriteFile(filepath.Join(root, "go.mod"), []byte("module example.com/report\n\ngo 1.19\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	filePath := filepath.Join(root, "report.go")
+	if err := os.WriteFile(filePath, []byte(sarifSource), 0644); err != nil {
+		t.Fatal(err)
+	}
+	// Relative URIs are escaped
+	spacedPath := filepath.Join(root, "with space", "spaced.go")
+	if err := os.MkdirAll(filepath.Dir(spacedPath), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(spacedPath, []byte("package spaced\n\nvar spaced_name = 1\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	result, err := analyzer.NewAnalyzer(analyzer.AnalysisOptions{
+		Analyzers:      []string{"naming"},
+		ColumnEncoding: analyzer.ColumnEncodingUTF16,
+	}).Analyze([]string{filePath, spacedPath})
+	if err != nil {
+		t.Fatalf("analysis failed: %v", err)
+	}
+	// Files outside the source root keep absolute URIs
+	result.Errors = append(result.Errors, analyzer.Error{Type: "parse", Message: "expected declaration", File: "/elsewhere/gen.go", Line: 3})
+
+	output, err := json.MarshalIndent(sarifLogOf(result, root), "", "  ")
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := []byte(strings.ReplaceAll(string(output), fileURI(root), "file:///project") + "\n")
+
+	goldenPath := filepath.Join("testdata", "report.sarif")
+	if *update {
+		if err := os.MkdirAll(filepath.Dir(goldenPath), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(goldenPath, got, 0644); err != nil {
+			t.Fatal(err)
+		}
+		return
+	}
+
+	want, err := os.ReadFile(goldenPath)
+	if err != nil {
+		t.Fatalf("cannot read golden file (run with -update to create it): %v", err)
+	}
+	if !bytes.Equal(got, want) {
+		t.Errorf("SARIF log differs from %s (run with -update to accept):\n%s", goldenPath, got)
+	}
+}
+
+func TestSARIFRegion(t *testing.T) {
+	tests := []struct {
+		name      string
+		violation analyzer.Violation
+		want      sarifRegion
+	}{
+		{"range", analyzer.Violation{Line: 3, Column: 5, EndLine: 4, EndColumn: 2}, sarifRegion{StartLine: 3, StartColumn: 5, EndLine: 4, EndColumn: 2}},
+		{"empty range", analyzer.Violation{Line: 3, Column: 5, EndLine: 3, EndColumn: 5}, sarifRegion{StartLine: 3, StartColumn: 5, EndLine: 3, EndColumn: 6}},
+		{"no position", analyzer.Violation{}, sarifRegion{StartLine: 1}},
+	}
+	for _, test := range tests {
+		if got := sarifRegionOf(test.violation); got != test.want {
+			t.Errorf("%s: got %+v, want %+v", test.name, got, test.want)
+		}
+	}
+}
+
+func TestSARIFExecutionSuccessful(t *testing.T) {
+	tests := []struct {
+		name   string
+		result *analyzer.AnalysisResult
+		want   bool
+	}{
+		{"violations only", resultWith("critical"), true},
+		{"analysis errors", &analyzer.AnalysisResult{Errors: []analyzer.Error{{Type: "parse", Message: "syntax error"}}}, false},
+	}
+	for _, test := range tests {
+		if got := sarifLogOf(test.result, "").Runs[0].Invocations[0].ExecutionSuccessful; got != test.want {
+			t.Errorf("%s: executionSuccessful %v, want %v", test.name, got, test.want)
+		}
+	}
+}
@@ -41,6 +41,7 @@ type AnalysisParams struct {
 type ContentAnalysisParams struct {
 	File    string                  `json:"file"`
 	Content string                  `json:"content"`
+	Overlay map[string]string       `json:"overlay"`
 	Options analyzer.AnalysisOptions `json:"options"`
 }
 
@@ -93,10 +94,12 @@ func handleRequest(req Request) {
 		handleAnalyze(req)
 	case "analyzeContent":
 		handleAnalyzeContent(req)
+	case "applyFixes":
+		handleApplyFixes(req)
 	case "ping":
 		sendResult("pong", req.ID)
 	case "version":
-		sendResult("1.0.0", req.ID)
+		sendResult(analyzer.Version, req.ID)
 	default:
 		sendError(-32601, "Method not found", req.ID)
 	}
@@ -184,15 +187,30 @@ func handleAnalyzeContent(req Request) {
 		return
 	}
 
-	// Validate file extension
-	if !strings.HasSuffix(params.File, ".go") {
-		sendError(-32603, "Not a Go file", req.ID)
+	// Combine the single file with any additional edited files
+	overlay := make(map[string]string)
+	for file, content := range params.Overlay {
+		overlay[file] = content
+	}
+	if params.File != "" {
+		overlay[params.File] = params.Content
+	}
+
+	// Validate file extensions
+	if len(overlay) == 0 {
+		sendError(-32603, "No Go files provided", req.ID)
 		return
 	}
+	for file := range overlay {
+		if !strings.HasSuffix(file, ".go") {
+			sendError(-32603, "Not a Go file", req.ID)
+			return
+		}
+	}
 
 	// Create and run analyzer with content
 	goAnalyzer := analyzer.NewAnalyzer(params.Options)
-	result, err := goAnalyzer.AnalyzeContent(params.File, params.Content)
+	result, err := goAnalyzer.AnalyzeOverlay(overlay)
 	if err != nil {
 		sendError(-32603, fmt.Sprintf("Content analysis failed: %v", err), req.ID)
 		return
@@ -200,4 +218,34 @@ func handleAnalyzeContent(req Request) {
 
 	// Send successful result
 	sendResult(result, req.ID)
-}
\ No newline at end of file
+}
+
+func handleApplyFixes(req Request) {
+	// Parse parameters
+	paramsBytes, err := json.Marshal(req.Params)
+	if err != nil {
+		sendError(-32602, "Invalid params", req.ID)
+		return
+	}
+
+	var params analyzer.ApplyFixesRequest
+	if err := json.Unmarshal(paramsBytes, &params); err != nil {
+		sendError(-32602, "Invalid params", req.ID)
+		return
+	}
+
+	if len(params.Violations) == 0 && len(params.Fingerprints) == 0 {
+		sendError(-32602, "No violations or fingerprints provided", req.ID)
+		return
+	}
+
+	// Apply the selected fixes
+	result, err := analyzer.ApplyFixes(params)
+	if err != nil {
+		sendError(-32603, fmt.Sprintf("Applying fixes failed: %v", err), req.ID)
+		return
+	}
+
+	// Send successful result
+	sendResult(result, req.ID)
+}
@@ -4,8 +4,10 @@ import (
 	"encoding/json"
 	"fmt"
 	"go/ast"
+	"go/importer"
 	"go/parser"
 	"go/token"
+	"go/types"
 	"io/ioutil"
 	"os"
 	"path/filepath"
@@ -70,6 +72,7 @@ type StructInfo struct {
 	Methods    []FunctionInfo   `json:"methods"`
 	Properties []PropertyInfo   `json:"properties"`
 	Extends    string           `json:"extends,omitempty"`
+	Embeds     []string         `json:"embeds"`
 	Implements []string         `json:"implements"`
 	IsAbstract bool             `json:"isAbstract"`
 	IsExported bool             `json:"isExported"`
@@ -222,9 +225,165 @@ func parseGoFile(filePath string, src string) ASTResponse {
 		return true
 	})
 
+	linkStructRelations(fset, node, &response)
+
 	return response
 }
 
+// linkStructRelations attaches methods, embeddings and satisfied interfaces to structs
+func linkStructRelations(fset *token.FileSet, node *ast.File, response *ASTResponse) {
+	methodKeys := map[string]map[string]bool{}
+	for _, decl := range node.Decls {
+		fn, ok := decl.(*ast.FuncDecl)
+		if !ok || fn.Recv == nil || len(fn.Recv.List) == 0 {
+			continue
+		}
+		recvName := cleanTypeName(types.ExprString(fn.Recv.List[0].Type))
+		if idx := strings.Index(recvName, "["); idx >= 0 {
+			recvName = recvName[:idx]
+		}
+		if methodKeys[recvName] == nil {
+			methodKeys[recvName] = map[string]bool{}
+		}
+		methodKeys[recvName][signatureKey(fn.Name.Name, fn.Type)] = true
+	}
+
+	interfaceKeys := map[string][]string{}
+	ast.Inspect(node, func(n ast.Node) bool {
+		typeSpec, ok := n.(*ast.TypeSpec)
+		if !ok {
+			return true
+		}
+		if interfaceType, ok := typeSpec.Type.(*ast.InterfaceType); ok {
+			interfaceKeys[typeSpec.Name.Name] = interfaceMethodKeys(interfaceType)
+		}
+		return true
+	})
+
+	checked := typeCheckFile(fset, node)
+
+	for i := range response.Structs {
+		structInfo := &response.Structs[i]
+		for _, fn := range response.Functions {
+			if fn.IsMethod && cleanTypeName(fn.ClassName) == structInfo.Name {
+				structInfo.Methods = append(structInfo.Methods, fn)
+			}
+		}
+
+		if checked != nil {
+			structInfo.Implements = typesImplements(checked, structInfo.Name)
+			continue
+		}
+
+		// Promote methods from embedded structs declared in this file
+		methodSet := map[string]bool{}
+		for key := range methodKeys[structInfo.Name] {
+			methodSet[key] = true
+		}
+		for _, embed := range structInfo.Embeds {
+			for key := range methodKeys[cleanTypeName(embed)] {
+				methodSet[key] = true
+			}
+		}
+		for _, iface := range response.Interfaces {
+			keys := interfaceKeys[iface.Name]
+			if len(iface.Extends) > 0 || len(keys) == 0 {
+				continue
+			}
+			satisfied := true
+			for _, key := range keys {
+				if !methodSet[key] {
+					satisfied = false
+					break
+				}
+			}
+			if satisfied {
+				structInfo.Implements = append(structInfo.Implements, iface.Name)
+			}
+		}
+	}
+}
+
+// typeCheckFile type-checks a single file, returning nil when it has errors
+func typeCheckFile(fset *token.FileSet, node *ast.File) *types.Package {
+	failed := false
+	config := types.Config{
+		Importer: importer.ForCompiler(fset, "source", nil),
+		Error:    func(err error) { failed = true },
+	}
+	pkg, _ := config.Check(node.Name.Name, fset, []*ast.File{node}, nil)
+	if failed {
+		return nil
+	}
+	return pkg
+}
+
+// typesImplements lists the non-empty interfaces in pkg satisfied by *structName
+func typesImplements(pkg *types.Package, structName string) []string {
+	implements := []string{}
+	obj, ok := pkg.Scope().Lookup(structName).(*types.TypeName)
+	if !ok {
+		return implements
+	}
+	named, ok := obj.Type().(*types.Named)
+	if !ok || named.TypeParams().Len() > 0 {
+		return implements
+	}
+	pointer := types.NewPointer(named)
+	for _, name := range pkg.Scope().Names() {
+		ifaceName, ok := pkg.Scope().Lookup(name).(*types.TypeName)
+		if !ok {
+			continue
+		}
+		ifaceNamed, ok := ifaceName.Type().(*types.Named)
+		if !ok || ifaceNamed.TypeParams().Len() > 0 {
+			continue
+		}
+		iface, ok := ifaceNamed.Underlying().(*types.Interface)
+		if !ok || iface.NumMethods() == 0 {
+			continue
+		}
+		if types.Implements(pointer, iface) {
+			implements = append(implements, name)
+		}
+	}
+	return implements
+}
+
+// interfaceMethodKeys returns the explicit method keys of an interface
+func interfaceMethodKeys(interfaceType *ast.InterfaceType) []string {
+	var keys []string
+	for _, method := range interfaceType.Methods.List {
+		if funcType, ok := method.Type.(*ast.FuncType); ok {
+			for _, name := range method.Names {
+				keys = append(keys, signatureKey(name.Name, funcType))
+			}
+		}
+	}
+	return keys
+}
+
+// signatureKey normalizes a method name and signature for comparison
+func signatureKey(name string, funcType *ast.FuncType) string {
+	return name + fieldListKey(funcType.Params) + fieldListKey(funcType.Results)
+}
+
+func fieldListKey(list *ast.FieldList) string {
+	var parts []string
+	if list != nil {
+		for _, field := range list.List {
+			count := len(field.Names)
+			if count == 0 {
+				count = 1
+			}
+			for i := 0; i < count; i++ {
+				parts = append(parts, types.ExprString(field.Type))
+			}
+		}
+	}
+	return "(" + strings.Join(parts, ",") + ")"
+}
+
 func extractFunctionInfo(fset *token.FileSet, fn *ast.FuncDecl) FunctionInfo {
 	pos := fset.Position(fn.Pos())
 	end := fset.Position(fn.End())
@@ -336,6 +495,9 @@ func extractInterfaceInfo(fset *token.FileSet, typeSpec *ast.TypeSpec) Interface
 			case *ast.Ident:
 				// Embedded interface
 				interfaceInfo.Extends = append(interfaceInfo.Extends, methodType.Name)
+			case *ast.SelectorExpr:
+				// Embedded interface from another package (io.Reader)
+				interfaceInfo.Extends = append(interfaceInfo.Extends, extractTypeString(methodType))
 			}
 		}
 	}
@@ -355,6 +517,7 @@ func extractStructInfo(fset *token.FileSet, typeSpec *ast.TypeSpec) StructInfo {
 		},
 		Methods:    []FunctionInfo{},
 		Properties: []PropertyInfo{},
+		Embeds:     []string{},
 		Implements: []string{},
 		IsAbstract: false,
 		IsExported: ast.IsExported(typeSpec.Name.Name),
@@ -365,7 +528,11 @@ func extractStructInfo(fset *token.FileSet, typeSpec *ast.TypeSpec) StructInfo {
 			fieldType := extractTypeString(field.Type)
 			
 			if len(field.Names) == 0 {
-				// Embedded field
+				// Embedded field; the first embedded type is reported as the base
+				structInfo.Embeds = append(structInfo.Embeds, fieldType)
+				if structInfo.Extends == "" {
+					structInfo.Extends = cleanTypeName(fieldType)
+				}
 				structInfo.Properties = append(structInfo.Properties, PropertyInfo{
 					Name:       cleanTypeName(fieldType),
 					Type:       fieldType,
@@ -161,11 +161,11 @@ func (pc *ProducerConsumer) Stop() {
 	})
 }
 
-func (pc *ProducerConsumer) GetResult() <-chan Result {
+func (pc *ProducerConsumer) GetResult() <-chan Result { // want "naming/getter-prefix: Getter GetResult should be named Result"
 	return pc.resultChannel
 }
 
-func (pc *ProducerConsumer) GetErrors() <-chan error {
+func (pc *ProducerConsumer) GetErrors() <-chan error { // want "naming/getter-prefix: Getter GetErrors should be named Errors"
 	return pc.errorChannel
 }
 
@@ -288,7 +288,7 @@ func (pp *PipelineProcessor) Start() {
 	}
 }
 
-func (pp *PipelineProcessor) runStage(stageID int, stage PipelineStage, input, output chan interface{}) {
+func (pp *PipelineProcessor) runStage(stageID int, stage PipelineStage, input, output chan interface{}) { // want "channels/concurrency: Complex function uses channels"
 	defer pp.wg.Done()
 	defer close(output)
 	
@@ -335,11 +335,11 @@ func (pp *PipelineProcessor) Stop() {
 	close(pp.errors)
 }
 
-func (pp *PipelineProcessor) GetOutput() <-chan interface{} {
+func (pp *PipelineProcessor) GetOutput() <-chan interface{} { // want "naming/getter-prefix: Getter GetOutput should be named Output"
 	return pp.output
 }
 
-func (pp *PipelineProcessor) GetErrors() <-chan error {
+func (pp *PipelineProcessor) GetErrors() <-chan error { // want "naming/getter-prefix: Getter GetErrors should be named Errors"
 	return pp.errors
 }
 
@@ -350,7 +350,7 @@ func LeakyGoroutinePattern() {
 	dataChan := make(chan string)
 	
 	// This goroutine will leak because dataChan is never closed or written to
-	go func() {
+	go func() { // want "goroutines/concurrency: (?i).*leak"
 		for data := range dataChan {
 			fmt.Printf("Processing: %s\n", data)
 		}
@@ -371,7 +371,7 @@ func RaceConditionPattern() {
 		go func() {
 			defer wg.Done()
 			// Race condition: multiple goroutines accessing globalCounter
-			globalCounter++
+			globalCounter++ // want "goroutines/concurrency: (?i).*race"
 			fmt.Printf("Counter: %d\n", globalCounter)
 		}()
 	}
@@ -384,7 +384,7 @@ func DeadlockPattern() {
 	ch1 := make(chan string)
 	ch2 := make(chan string)
 	
-	go func() {
+	go func() { // want "channels/concurrency: (?i).*deadlock"
 		ch1 <- "message1"
 		msg := <-ch2
 		fmt.Printf("Received: %s\n", msg)
@@ -407,7 +407,7 @@ func UnboundedGoroutinePattern(items []string) {
 	// Problem: creating one goroutine per item without limiting concurrency
 	for _, item := range items {
 		wg.Add(1)
-		go func(data string) {
+		go func(data string) { // want "goroutines/concurrency: (?i).*unbounded"
 			defer wg.Done()
 			// Simulate work
 			time.Sleep(time.Duration(rand.Intn(1000)) * time.Millisecond)
@@ -159,7 +159,7 @@ func (s *UserService) GetUser(ctx context.Context, id string) (*User, error) {
 // Advanced interface patterns
 
 // Strategy pattern with interfaces
-type ValidationStrategy interface {
+type ValidationStrategy interface { // want "naming/interface-naming: Single-method interface ValidationStrategy"
 	Validate(user *User) error
 }
 
@@ -14,7 +14,7 @@ import (
 // SRP Violations - Functions doing too many things
 
 // MegaProcessor violates SRP by handling validation, processing, logging, caching, and notifications
-func MegaProcessor(data string, userID int, config map[string]interface{}, ctx context.Context) (result string, cached bool, processed bool, notifications []string, errors []error) {
+func MegaProcessor(data string, userID int, config map[string]interface{}, ctx context.Context) (result string, cached bool, processed bool, notifications []string, errors []error) { // want "solid/single-responsibility"
 	// Validation logic
 	if len(data) == 0 {
 		errors = append(errors, fmt.Errorf("empty data"))
@@ -89,7 +89,7 @@ type ReportGenerator struct {
 
 // GenerateReport violates OCP - adding new formats requires modifying this function
 func (r *ReportGenerator) GenerateReport() string {
-	switch r.format {
+	switch r.format { // want "solid/open-closed: Large switch statement"
 	case "pdf":
 		return r.generatePDF()
 	case "excel":
@@ -163,7 +163,7 @@ func (s *Square) SetHeight(height float64) {
 // ISP Violations - Interfaces too large forcing clients to depend on methods they don't use
 
 // MegaInterface violates ISP by combining unrelated responsibilities
-type MegaInterface interface {
+type MegaInterface interface { // want "solid/interface-segregation: Interface has too many methods"
 	// Database operations
 	Connect() error
 	Disconnect() error
@@ -195,8 +195,8 @@ type MegaInterface interface {
 
 // DIP Violations - High-level modules depending on low-level modules
 
-// UserService violates DIP by directly depending on concrete implementations
-type UserService struct {
+// AccountService violates DIP by directly depending on concrete implementations
+type AccountService struct { // want "solid/dependency-inversion"
 	mysql    *MySQLDatabase    // Direct dependency on concrete class
 	redis    *RedisCache       // Direct dependency on concrete class
 	mailer   *SMTPMailer       // Direct dependency on concrete class
@@ -264,9 +264,9 @@ func (r *RegexValidator) ValidatePhone(phone string) bool {
 	return len(phone) >= 10
 }
 
-// NewUserService creates a new UserService with all concrete dependencies
-func NewUserService() *UserService {
-	return &UserService{
+// NewAccountService creates a new AccountService with all concrete dependencies
+func NewAccountService() *AccountService {
+	return &AccountService{
 		mysql: &MySQLDatabase{
 			connectionString: "user:pass@tcp(localhost:3306)/db",
 		},
@@ -291,7 +291,7 @@ func NewUserService() *UserService {
 }
 
 // CreateUser demonstrates multiple SOLID violations in one method
-func (u *UserService) CreateUser(email, name, phone string) error {
+func (u *AccountService) CreateUser(email, name, phone string) error { // want "errors/error-handling"
 	// Direct dependency usage (DIP violation)
 	if !u.validator.ValidateEmail(email) {
 		u.logger.Log(fmt.Sprintf("Invalid email: %s", email))
@@ -310,6 +310,7 @@ func (u *UserService) CreateUser(email, name, phone string) error {
 		"phone": phone,
 		"created_at": time.Now(),
 	}
+	u.logger.Log(fmt.Sprintf("Creating user: %v", userData))
 	
 	// Database operations
 	if err := u.mysql.Connect(); err != nil {